@@ -0,0 +1,87 @@
+package bls
+
+import (
+	"errors"
+)
+
+// The affine coordinates of points are big-endian field elements of 48 bytes. The elements of Fp2 of G2 are
+// written c0 || c1, as in EIP-2537 and unlike the ZCash format which starts with c1. The point at infinity
+// has the coordinates (0, 0).
+
+var errAffineLength = errors.New("bls12-381: invalid affine coordinate length")
+
+// swapFp2 returns the halves of buf in the reverse order, converting an element of Fp2 between c0 || c1 and
+// the c1 || c0 of the ZCash format.
+func swapFp2(buf []byte) []byte {
+	return append(append([]byte(nil), buf[48:]...), buf[:48]...)
+}
+
+// Affine returns the affine coordinates of k, or nil when k does not hold a point.
+func (k *KyberG1) Affine() (x, y []byte) {
+	if k.p == nil {
+		return nil, nil
+	}
+	raw := k.p.uncompressed()
+	return raw[:48], raw[48:]
+}
+
+// FromAffine sets k to the point with the affine coordinates x and y, returning an error if they are not
+// canonical or the point is not on the curve. The point is not checked to be in G1, see IsInCorrectGroup.
+func (k *KyberG1) FromAffine(x, y []byte) error {
+	if len(x) != 48 || len(y) != 48 {
+		return errAffineLength
+	}
+	k.backend = backendOrDefault(k.backend)
+	p, err := k.backend.g1().fromUncompressed(append(append([]byte(nil), x...), y...))
+	if err != nil {
+		return err
+	}
+	k.p, k.outside = p, !p.inSubgroup()
+	return nil
+}
+
+// IsInfinity returns whether k is the point at infinity, the neutral element of G1.
+func (k *KyberG1) IsInfinity() bool {
+	return k.p != nil && k.p.isInfinity()
+}
+
+// IsOnCurve returns whether k is a point of E1, in G1 or not. It returns false when k does not hold a point.
+func (k *KyberG1) IsOnCurve() bool {
+	return k.p != nil && k.p.onCurve()
+}
+
+// Affine returns the affine coordinates of k, each written c0 || c1 as in EIP-2537, or nil when k does not
+// hold a point. This is the reverse of the order c1 || c0 of Fp2.Bytes and of the ZCash format.
+func (k *KyberG2) Affine() (x, y []byte) {
+	if k.p == nil {
+		return nil, nil
+	}
+	raw := k.p.uncompressed()
+	return swapFp2(raw[:96]), swapFp2(raw[96:])
+}
+
+// FromAffine sets k to the point with the affine coordinates x and y, each written c0 || c1 as by Affine and
+// unlike Fp2.Bytes, returning an error if they are not canonical or the point is not on the curve. The point
+// is not checked to be in G2, see IsInCorrectGroup.
+func (k *KyberG2) FromAffine(x, y []byte) error {
+	if len(x) != 96 || len(y) != 96 {
+		return errAffineLength
+	}
+	k.backend = backendOrDefault(k.backend)
+	p, err := k.backend.g2().fromUncompressed(append(swapFp2(x), swapFp2(y)...))
+	if err != nil {
+		return err
+	}
+	k.p, k.outside = p, !p.inSubgroup()
+	return nil
+}
+
+// IsInfinity returns whether k is the point at infinity, the neutral element of G2.
+func (k *KyberG2) IsInfinity() bool {
+	return k.p != nil && k.p.isInfinity()
+}
+
+// IsOnCurve returns whether k is a point of E2, in G2 or not. It returns false when k does not hold a point.
+func (k *KyberG2) IsOnCurve() bool {
+	return k.p != nil && k.p.onCurve()
+}
@@ -0,0 +1,133 @@
+package bls
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/drand/kyber"
+	"github.com/drand/kyber/group/mod"
+	"github.com/drand/kyber/util/random"
+	"github.com/stretchr/testify/require"
+)
+
+// outsideSubgroup returns the affine coordinates of a point of the curve outside of the subgroup, from the
+// negative corpus.
+func outsideSubgroup(t *testing.T, file string) []byte {
+	var corpus negativeCorpus
+	loadJSON(t, "testdata/negative/"+file, &corpus)
+	for _, tc := range corpus.Tests {
+		if tc.Point != "" && tc.Result == "invalid" {
+			return decodeHex(t, tc.Point)
+		}
+	}
+	t.Fatalf("no point outside of the subgroup in %s", file)
+	return nil
+}
+
+// requireMulOutside checks Mul on a point outside of the subgroup against double-and-add with Add.
+func requireMulOutside(t *testing.T, p kyber.Point) {
+	s := NewKyberScalar().Pick(random.New())
+	expected := p.Clone().Null()
+	for i := s.(*mod.Int).V.BitLen() - 1; i >= 0; i-- {
+		expected.Add(expected, expected)
+		if s.(*mod.Int).V.Bit(i) == 1 {
+			expected.Add(expected, p)
+		}
+	}
+	require.True(t, expected.Equal(p.Clone().Mul(s, p)))
+}
+
+func TestAffineG1(t *testing.T) {
+	x := decodeHex(t, "17f1d3a73197d7942695638c4fa9ac0fc3688c4f9774b905a14e3a3f171bac586c55e83ff97a1aeffb3af00adb22c6bb")
+	y := decodeHex(t, "08b3f481e3aaa0f1a09e30ed741d8ae4fcf5e095d5d00af600db18cb2c04b3edd03cc744a2888ae40caa232946c5e7e1")
+	outside := outsideSubgroup(t, "g1.json")
+	for _, b := range backends {
+		t.Run(b.String(), func(t *testing.T) {
+			g := newGroupG1(b, nil, nil, false, false)
+			base := g.Point().Base().(*KyberG1)
+			bx, by := base.Affine()
+			require.Equal(t, x, bx)
+			require.Equal(t, y, by)
+			require.False(t, base.IsInfinity())
+			require.True(t, base.IsOnCurve())
+
+			p := g.Point().(*KyberG1)
+			require.NoError(t, p.FromAffine(x, y))
+			require.True(t, p.Equal(base))
+
+			null := g.Point().Null().(*KyberG1)
+			require.True(t, null.IsInfinity())
+			require.True(t, null.IsOnCurve())
+			nx, ny := null.Affine()
+			require.Equal(t, make([]byte, 48), nx)
+			require.Equal(t, make([]byte, 48), ny)
+			require.NoError(t, p.FromAffine(nx, ny))
+			require.True(t, p.IsInfinity())
+
+			// points outside of the subgroup are accepted, and reported by IsInCorrectGroup
+			require.NoError(t, p.FromAffine(outside[:48], outside[48:]))
+			require.True(t, p.IsOnCurve())
+			require.False(t, p.IsInCorrectGroup())
+			requireMulOutside(t, p)
+
+			bad := append([]byte(nil), y...)
+			bad[47] ^= 1
+			require.Error(t, p.FromAffine(x, bad), "not on the curve")
+			require.Error(t, p.FromAffine(x[1:], y), "wrong length")
+			xp := new(big.Int).Add(new(big.Int).SetBytes(x), fieldModulus).FillBytes(make([]byte, 48))
+			require.Error(t, p.FromAffine(xp, y), "not canonical")
+			require.False(t, new(KyberG1).IsOnCurve())
+			require.False(t, new(KyberG1).IsInfinity())
+		})
+	}
+}
+
+func TestAffineG2(t *testing.T) {
+	x := decodeHex(t, "024aa2b2f08f0a91260805272dc51051c6e47ad4fa403b02b4510b647ae3d1770bac0326a805bbefd48056c8c121bdb8"+
+		"13e02b6052719f607dacd3a088274f65596bd0d09920b61ab5da61bbdc7f5049334cf11213945d57e5ac7d055d042b7e")
+	y := decodeHex(t, "0ce5d527727d6e118cc9cdc6da2e351aadfd9baa8cbdd3a76d429a695160d12c923ac9cc3baca289e193548608b82801"+
+		"0606c4a02ea734cc32acd2b02bc28b99cb3e287e85a763af267492ab572e99ab3f370d275cec1da1aaa9075ff05f79be")
+	outside := outsideSubgroup(t, "g2.json")
+	for _, b := range backends {
+		t.Run(b.String(), func(t *testing.T) {
+			g := newGroupG2(b, nil, nil, false, false)
+			base := g.Point().Base().(*KyberG2)
+			bx, by := base.Affine()
+			require.Equal(t, x, bx)
+			require.Equal(t, y, by)
+			require.False(t, base.IsInfinity())
+			require.True(t, base.IsOnCurve())
+
+			p := g.Point().(*KyberG2)
+			require.NoError(t, p.FromAffine(x, y))
+			require.True(t, p.Equal(base))
+
+			null := g.Point().Null().(*KyberG2)
+			require.True(t, null.IsInfinity())
+			nx, ny := null.Affine()
+			require.NoError(t, p.FromAffine(nx, ny))
+			require.True(t, p.IsInfinity())
+
+			require.NoError(t, p.FromAffine(swapFp2(outside[:96]), swapFp2(outside[96:])))
+			require.True(t, p.IsOnCurve())
+			require.False(t, p.IsInCorrectGroup())
+			requireMulOutside(t, p)
+
+			// the ZCash order of the coefficients gives another point
+			require.Error(t, p.FromAffine(swapFp2(x), swapFp2(y)))
+			require.Error(t, p.FromAffine(x[:48], y[:48]), "wrong length")
+		})
+	}
+}
+
+// TestAffineG2Order checks that the coordinates are written c0 || c1, unlike Fp2.Bytes.
+func TestAffineG2Order(t *testing.T) {
+	x, y := IsogenyMapG2(MapToCurveSSWUG2(fp2FromHex("2a", "7")))
+	p, err := MapToCurveG2(fp2FromHex("2a", "7"))
+	require.NoError(t, err)
+	px, py := p.Affine()
+	require.Equal(t, append(fpToBytes(x.C0), fpToBytes(x.C1)...), px)
+	require.Equal(t, swapFp2(x.Bytes()), px)
+	require.Equal(t, swapFp2(y.Bytes()), py)
+	require.NotEqual(t, x.Bytes(), px)
+}
@@ -0,0 +1,118 @@
+package bls
+
+import (
+	"math/big"
+)
+
+// Backend is an implementation of the BLS12-381 arithmetic behind KyberG1, KyberG2, KyberGT and Suite.
+// All backends use the same encodings, so that points and signatures can be exchanged between them, and
+// points of different backends can be mixed in the same operation. See WithBackend.
+type Backend interface {
+	// String returns the name of the backend.
+	String() string
+
+	g1() curve
+	g2() curve
+	gtOne() gtElement
+	// gtFromBytes decodes an element of Fp12, returning it along with an error if it is not in GT.
+	gtFromBytes(in []byte) (gtElement, error)
+	// pair returns the product of the pairings of the points of p with the points of q.
+	pair(p, q []point) gtElement
+	// pairingCheck returns whether the product of the pairings of the points of p with the points of q is one.
+	pairingCheck(p, q []point) bool
+}
+
+var (
+	// BackendKilic is the github.com/kilic/bls12-381 implementation, used by default.
+	BackendKilic Backend = kilicBackend{}
+	// BackendGnark is the pure Go github.com/consensys/gnark-crypto implementation.
+	BackendGnark Backend = gnarkBackend{}
+)
+
+// defaultBackend is the backend of the points created outside of a Suite.
+var defaultBackend = BackendKilic
+
+func backendOrDefault(b Backend) Backend {
+	if b == nil {
+		return defaultBackend
+	}
+	return b
+}
+
+// curve holds the operations of a backend on E1 or E2.
+type curve interface {
+	zero() point
+	generator() point
+	// fromCompressed decodes a point of the subgroup in the ZCash compressed format.
+	fromCompressed(in []byte) (point, error)
+	// fromUncompressed decodes a point of the curve, not necessarily in the subgroup, from its affine
+	// coordinates x || y without flags, (0, 0) being the point at infinity.
+	fromUncompressed(in []byte) (point, error)
+	// hashToCurve implements the RFC 9380 random oracle suite with expand_message_xmd and SHA-256, for tags
+	// of at most 255 bytes.
+	hashToCurve(msg, dst []byte) (point, error)
+	// mapToCurve maps a field element to the subgroup: the simplified SWU map, followed by the isogeny and
+	// clear_cofactor. The element is encoded as by fpToBytes for Fp, and Fp2.Bytes for Fp2.
+	mapToCurve(u []byte) (point, error)
+	// multiExp returns the sum of the points of ps multiplied by the scalars of ss, which are in [0, r) and
+	// have the same length as ps.
+	multiExp(ps []point, ss []*big.Int) point
+}
+
+// point is a point of E1 or E2 in the representation of a backend. The points passed to its methods must
+// come from the same curve of the same backend.
+type point interface {
+	backend() Backend
+	set(q point)
+	clone() point
+	add(a, b point)
+	sub(a, b point)
+	neg(a point)
+	// mul sets the point to s a with the GLV method of the backend, which is only correct for a in the subgroup.
+	mul(a point, s *big.Int)
+	// mulAny sets the point to s a for any point a of the curve, with double-and-add.
+	mulAny(a point, s *big.Int)
+	equal(q point) bool
+	isInfinity() bool
+	onCurve() bool
+	inSubgroup() bool
+	clearCofactor()
+	compressed() []byte
+	// uncompressed returns the encoding read by curve.fromUncompressed.
+	uncompressed() []byte
+}
+
+// gtElement is an element of Fp12 in the representation of a backend.
+type gtElement interface {
+	backend() Backend
+	set(q gtElement)
+	mul(a, b gtElement)
+	inverse(a gtElement)
+	exp(a gtElement, s *big.Int)
+	equal(q gtElement) bool
+	inSubgroup() bool
+	bytes() []byte
+}
+
+// convertPoint returns p in the representation of the curve c of the backend b.
+func convertPoint(b Backend, c curve, p point) point {
+	if p.backend() == b {
+		return p
+	}
+	// the uncompressed encoding avoids computing a square root, and the point is known to be on the curve
+	q, err := c.fromUncompressed(p.uncompressed())
+	if err != nil {
+		panic("bls12-381: cannot convert point between backends: " + err.Error())
+	}
+	return q
+}
+
+// convertGT returns e in the representation of the backend b.
+func convertGT(b Backend, e gtElement) gtElement {
+	if e.backend() == b {
+		return e
+	}
+	// elements outside of GT are converted as well, the error only reports them
+	f, _ := b.gtFromBytes(e.bytes())
+	return f
+}
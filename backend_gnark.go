@@ -0,0 +1,431 @@
+package bls
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	gnark "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+var (
+	errGnarkCompressed = errors.New("bls12-381: invalid compressed point encoding")
+	errGnarkLength     = errors.New("bls12-381: invalid field element encoding length")
+	errGnarkNotOnCurve = errors.New("bls12-381: point is not on curve")
+	errGnarkGT         = errors.New("bls12-381: element is not in GT")
+)
+
+type gnarkBackend struct{}
+
+func (gnarkBackend) String() string {
+	return "gnark"
+}
+
+func (gnarkBackend) g1() curve {
+	return gnarkG1{}
+}
+
+func (gnarkBackend) g2() curve {
+	return gnarkG2{}
+}
+
+func (gnarkBackend) gtOne() gtElement {
+	e := new(gnarkGT)
+	(*gnark.GT)(e).SetOne()
+	return e
+}
+
+func (gnarkBackend) gtFromBytes(in []byte) (gtElement, error) {
+	e := new(gnarkGT)
+	if err := (*gnark.GT)(e).SetBytes(in); err != nil {
+		return nil, err
+	}
+	if !e.inSubgroup() {
+		return e, errGnarkGT
+	}
+	return e, nil
+}
+
+func (gnarkBackend) pair(p, q []point) gtElement {
+	ps, qs := gnarkPairs(p, q)
+	// the lengths always match, which is the only error
+	e, _ := gnark.Pair(ps, qs)
+	return (*gnarkGT)(&e)
+}
+
+func (gnarkBackend) pairingCheck(p, q []point) bool {
+	ps, qs := gnarkPairs(p, q)
+	ok, _ := gnark.PairingCheck(ps, qs)
+	return ok
+}
+
+func gnarkPairs(p, q []point) ([]gnark.G1Affine, []gnark.G2Affine) {
+	ps := make([]gnark.G1Affine, len(p))
+	qs := make([]gnark.G2Affine, len(q))
+	for i := range p {
+		ps[i] = *gnarkG1Of(p[i])
+		qs[i] = *gnarkG2Of(q[i])
+	}
+	return ps, qs
+}
+
+type gnarkG1 struct{}
+
+type gnarkG1Point gnark.G1Affine
+
+func gnarkG1Of(p point) *gnark.G1Affine {
+	return (*gnark.G1Affine)(p.(*gnarkG1Point))
+}
+
+func (gnarkG1) zero() point {
+	return new(gnarkG1Point)
+}
+
+func (gnarkG1) generator() point {
+	_, _, g, _ := gnark.Generators()
+	return (*gnarkG1Point)(&g)
+}
+
+func (gnarkG1) fromCompressed(in []byte) (point, error) {
+	// SetBytes also accepts longer buffers and uncompressed points
+	if len(in) != gnark.SizeOfG1AffineCompressed || in[0]&0x80 == 0 {
+		return nil, errGnarkCompressed
+	}
+	p := new(gnark.G1Affine)
+	if _, err := p.SetBytes(in); err != nil {
+		return nil, err
+	}
+	return (*gnarkG1Point)(p), nil
+}
+
+func (gnarkG1) fromUncompressed(in []byte) (point, error) {
+	if len(in) != 2*fp.Bytes {
+		return nil, errGnarkLength
+	}
+	p := new(gnark.G1Affine)
+	if err := p.X.SetBytesCanonical(in[:fp.Bytes]); err != nil {
+		return nil, err
+	}
+	if err := p.Y.SetBytesCanonical(in[fp.Bytes:]); err != nil {
+		return nil, err
+	}
+	// gnark also represents the point at infinity as (0, 0)
+	if !p.IsOnCurve() {
+		return nil, errGnarkNotOnCurve
+	}
+	return (*gnarkG1Point)(p), nil
+}
+
+func (gnarkG1) hashToCurve(msg, dst []byte) (point, error) {
+	p, err := gnark.HashToG1(msg, dst)
+	if err != nil {
+		return nil, err
+	}
+	return (*gnarkG1Point)(&p), nil
+}
+
+func (gnarkG1) mapToCurve(u []byte) (point, error) {
+	var e fp.Element
+	if err := e.SetBytesCanonical(u); err != nil {
+		return nil, err
+	}
+	p := gnark.MapToG1(e)
+	return (*gnarkG1Point)(&p), nil
+}
+
+func (gnarkG1) multiExp(ps []point, ss []*big.Int) point {
+	points := make([]gnark.G1Affine, len(ps))
+	for i, p := range ps {
+		points[i] = *gnarkG1Of(p)
+	}
+	scalars := make([]fr.Element, len(ss))
+	for i, s := range ss {
+		scalars[i].SetBigInt(s)
+	}
+	r := new(gnark.G1Affine)
+	// the lengths always match and the default configuration is valid, which are the only errors
+	_, _ = r.MultiExp(points, scalars, ecc.MultiExpConfig{})
+	return (*gnarkG1Point)(r)
+}
+
+func (p *gnarkG1Point) backend() Backend {
+	return BackendGnark
+}
+
+func (p *gnarkG1Point) set(q point) {
+	*p = *(*gnarkG1Point)(gnarkG1Of(q))
+}
+
+func (p *gnarkG1Point) clone() point {
+	q := *p
+	return &q
+}
+
+func (p *gnarkG1Point) add(a, b point) {
+	(*gnark.G1Affine)(p).Add(gnarkG1Of(a), gnarkG1Of(b))
+}
+
+func (p *gnarkG1Point) sub(a, b point) {
+	(*gnark.G1Affine)(p).Sub(gnarkG1Of(a), gnarkG1Of(b))
+}
+
+func (p *gnarkG1Point) neg(a point) {
+	(*gnark.G1Affine)(p).Neg(gnarkG1Of(a))
+}
+
+func (p *gnarkG1Point) mul(a point, s *big.Int) {
+	(*gnark.G1Affine)(p).ScalarMultiplication(gnarkG1Of(a), s)
+}
+
+func (p *gnarkG1Point) mulAny(a point, s *big.Int) {
+	aa := gnarkG1Of(a)
+	var r gnark.G1Jac
+	r.FromAffine(new(gnark.G1Affine))
+	for i := s.BitLen() - 1; i >= 0; i-- {
+		r.DoubleAssign()
+		if s.Bit(i) == 1 {
+			r.AddMixed(aa)
+		}
+	}
+	(*gnark.G1Affine)(p).FromJacobian(&r)
+}
+
+func (p *gnarkG1Point) equal(q point) bool {
+	return (*gnark.G1Affine)(p).Equal(gnarkG1Of(q))
+}
+
+func (p *gnarkG1Point) isInfinity() bool {
+	return (*gnark.G1Affine)(p).IsInfinity()
+}
+
+func (p *gnarkG1Point) onCurve() bool {
+	return (*gnark.G1Affine)(p).IsOnCurve()
+}
+
+func (p *gnarkG1Point) inSubgroup() bool {
+	return (*gnark.G1Affine)(p).IsInSubGroup()
+}
+
+func (p *gnarkG1Point) clearCofactor() {
+	(*gnark.G1Affine)(p).ClearCofactor((*gnark.G1Affine)(p))
+}
+
+func (p *gnarkG1Point) compressed() []byte {
+	b := (*gnark.G1Affine)(p).Bytes()
+	return b[:]
+}
+
+func (p *gnarkG1Point) uncompressed() []byte {
+	if (*gnark.G1Affine)(p).IsInfinity() {
+		return make([]byte, 2*fp.Bytes)
+	}
+	b := (*gnark.G1Affine)(p).RawBytes()
+	return b[:]
+}
+
+type gnarkG2 struct{}
+
+type gnarkG2Point gnark.G2Affine
+
+func gnarkG2Of(p point) *gnark.G2Affine {
+	return (*gnark.G2Affine)(p.(*gnarkG2Point))
+}
+
+func (gnarkG2) zero() point {
+	return new(gnarkG2Point)
+}
+
+func (gnarkG2) generator() point {
+	_, _, _, g := gnark.Generators()
+	return (*gnarkG2Point)(&g)
+}
+
+func (gnarkG2) fromCompressed(in []byte) (point, error) {
+	// SetBytes also accepts longer buffers and uncompressed points
+	if len(in) != gnark.SizeOfG2AffineCompressed || in[0]&0x80 == 0 {
+		return nil, errGnarkCompressed
+	}
+	p := new(gnark.G2Affine)
+	if _, err := p.SetBytes(in); err != nil {
+		return nil, err
+	}
+	return (*gnarkG2Point)(p), nil
+}
+
+func (gnarkG2) fromUncompressed(in []byte) (point, error) {
+	if len(in) != 4*fp.Bytes {
+		return nil, errGnarkLength
+	}
+	p := new(gnark.G2Affine)
+	if err := setE2(&p.X, in[:2*fp.Bytes]); err != nil {
+		return nil, err
+	}
+	if err := setE2(&p.Y, in[2*fp.Bytes:]); err != nil {
+		return nil, err
+	}
+	// gnark also represents the point at infinity as (0, 0)
+	if !p.IsOnCurve() {
+		return nil, errGnarkNotOnCurve
+	}
+	return (*gnarkG2Point)(p), nil
+}
+
+// setE2 decodes an element of Fp2 encoded as c1 || c0.
+func setE2(e *gnark.E2, in []byte) error {
+	if err := e.A1.SetBytesCanonical(in[:fp.Bytes]); err != nil {
+		return err
+	}
+	return e.A0.SetBytesCanonical(in[fp.Bytes:])
+}
+
+func (gnarkG2) hashToCurve(msg, dst []byte) (point, error) {
+	p, err := gnark.HashToG2(msg, dst)
+	if err != nil {
+		return nil, err
+	}
+	return (*gnarkG2Point)(&p), nil
+}
+
+func (gnarkG2) mapToCurve(u []byte) (point, error) {
+	var e gnark.E2
+	if len(u) != 2*fp.Bytes {
+		return nil, errGnarkLength
+	}
+	if err := setE2(&e, u); err != nil {
+		return nil, err
+	}
+	p := gnark.MapToG2(e)
+	return (*gnarkG2Point)(&p), nil
+}
+
+func (gnarkG2) multiExp(ps []point, ss []*big.Int) point {
+	points := make([]gnark.G2Affine, len(ps))
+	for i, p := range ps {
+		points[i] = *gnarkG2Of(p)
+	}
+	scalars := make([]fr.Element, len(ss))
+	for i, s := range ss {
+		scalars[i].SetBigInt(s)
+	}
+	r := new(gnark.G2Affine)
+	// the lengths always match and the default configuration is valid, which are the only errors
+	_, _ = r.MultiExp(points, scalars, ecc.MultiExpConfig{})
+	return (*gnarkG2Point)(r)
+}
+
+func (p *gnarkG2Point) backend() Backend {
+	return BackendGnark
+}
+
+func (p *gnarkG2Point) set(q point) {
+	*p = *(*gnarkG2Point)(gnarkG2Of(q))
+}
+
+func (p *gnarkG2Point) clone() point {
+	q := *p
+	return &q
+}
+
+func (p *gnarkG2Point) add(a, b point) {
+	(*gnark.G2Affine)(p).Add(gnarkG2Of(a), gnarkG2Of(b))
+}
+
+func (p *gnarkG2Point) sub(a, b point) {
+	(*gnark.G2Affine)(p).Sub(gnarkG2Of(a), gnarkG2Of(b))
+}
+
+func (p *gnarkG2Point) neg(a point) {
+	(*gnark.G2Affine)(p).Neg(gnarkG2Of(a))
+}
+
+func (p *gnarkG2Point) mul(a point, s *big.Int) {
+	(*gnark.G2Affine)(p).ScalarMultiplication(gnarkG2Of(a), s)
+}
+
+func (p *gnarkG2Point) mulAny(a point, s *big.Int) {
+	aa := gnarkG2Of(a)
+	var r gnark.G2Jac
+	r.FromAffine(new(gnark.G2Affine))
+	for i := s.BitLen() - 1; i >= 0; i-- {
+		r.DoubleAssign()
+		if s.Bit(i) == 1 {
+			r.AddMixed(aa)
+		}
+	}
+	(*gnark.G2Affine)(p).FromJacobian(&r)
+}
+
+func (p *gnarkG2Point) equal(q point) bool {
+	return (*gnark.G2Affine)(p).Equal(gnarkG2Of(q))
+}
+
+func (p *gnarkG2Point) isInfinity() bool {
+	return (*gnark.G2Affine)(p).IsInfinity()
+}
+
+func (p *gnarkG2Point) onCurve() bool {
+	return (*gnark.G2Affine)(p).IsOnCurve()
+}
+
+func (p *gnarkG2Point) inSubgroup() bool {
+	return (*gnark.G2Affine)(p).IsInSubGroup()
+}
+
+func (p *gnarkG2Point) clearCofactor() {
+	(*gnark.G2Affine)(p).ClearCofactor((*gnark.G2Affine)(p))
+}
+
+func (p *gnarkG2Point) compressed() []byte {
+	b := (*gnark.G2Affine)(p).Bytes()
+	return b[:]
+}
+
+func (p *gnarkG2Point) uncompressed() []byte {
+	if (*gnark.G2Affine)(p).IsInfinity() {
+		return make([]byte, 2*2*fp.Bytes)
+	}
+	b := (*gnark.G2Affine)(p).RawBytes()
+	return b[:]
+}
+
+type gnarkGT gnark.GT
+
+func gnarkGTOf(e gtElement) *gnark.GT {
+	return (*gnark.GT)(e.(*gnarkGT))
+}
+
+func (e *gnarkGT) backend() Backend {
+	return BackendGnark
+}
+
+func (e *gnarkGT) set(q gtElement) {
+	(*gnark.GT)(e).Set(gnarkGTOf(q))
+}
+
+func (e *gnarkGT) mul(a, b gtElement) {
+	(*gnark.GT)(e).Mul(gnarkGTOf(a), gnarkGTOf(b))
+}
+
+func (e *gnarkGT) inverse(a gtElement) {
+	(*gnark.GT)(e).Inverse(gnarkGTOf(a))
+}
+
+func (e *gnarkGT) exp(a gtElement, s *big.Int) {
+	(*gnark.GT)(e).Exp(*gnarkGTOf(a), s)
+}
+
+func (e *gnarkGT) equal(q gtElement) bool {
+	return (*gnark.GT)(e).Equal(gnarkGTOf(q))
+}
+
+func (e *gnarkGT) inSubgroup() bool {
+	// the check of gnark relies on the Frobenius map, which zero also passes
+	return !(*gnark.GT)(e).IsZero() && (*gnark.GT)(e).IsInSubGroup()
+}
+
+func (e *gnarkGT) bytes() []byte {
+	b := (*gnark.GT)(e).Bytes()
+	return b[:]
+}
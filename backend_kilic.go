@@ -0,0 +1,335 @@
+package bls
+
+import (
+	"math/big"
+
+	bls12381 "github.com/kilic/bls12-381"
+)
+
+type kilicBackend struct{}
+
+func (kilicBackend) String() string {
+	return "kilic"
+}
+
+func (kilicBackend) g1() curve {
+	return kilicG1{}
+}
+
+func (kilicBackend) g2() curve {
+	return kilicG2{}
+}
+
+func (kilicBackend) gtOne() gtElement {
+	return (*kilicGT)(bls12381.NewGT().New())
+}
+
+func (kilicBackend) gtFromBytes(in []byte) (gtElement, error) {
+	e, err := bls12381.NewGT().FromBytes(in)
+	if e == nil {
+		return nil, err
+	}
+	return (*kilicGT)(e), err
+}
+
+func (kilicBackend) pair(p, q []point) gtElement {
+	return (*kilicGT)(kilicEngine(p, q).Result())
+}
+
+func (kilicBackend) pairingCheck(p, q []point) bool {
+	return kilicEngine(p, q).Check()
+}
+
+func kilicEngine(p, q []point) *bls12381.Engine {
+	e := bls12381.NewEngine()
+	for i := range p {
+		// we need to clone the point because of https://github.com/kilic/bls12-381/issues/37
+		// in order to avoid risks of race conditions.
+		g1point := new(bls12381.PointG1).Set(kilicG1Of(p[i]))
+		g2point := new(bls12381.PointG2).Set(kilicG2Of(q[i]))
+		e.AddPair(g1point, g2point)
+	}
+	return e
+}
+
+type kilicG1 struct{}
+
+type kilicG1Point bls12381.PointG1
+
+func kilicG1Of(p point) *bls12381.PointG1 {
+	return (*bls12381.PointG1)(p.(*kilicG1Point))
+}
+
+func newKilicG1Point(p *bls12381.PointG1, err error) (point, error) {
+	if err != nil {
+		return nil, err
+	}
+	return (*kilicG1Point)(p), nil
+}
+
+func (kilicG1) zero() point {
+	return (*kilicG1Point)(bls12381.NewG1().Zero())
+}
+
+func (kilicG1) generator() point {
+	return (*kilicG1Point)(bls12381.NewG1().One())
+}
+
+func (kilicG1) fromCompressed(in []byte) (point, error) {
+	return newKilicG1Point(bls12381.NewG1().FromCompressed(in))
+}
+
+func (kilicG1) fromUncompressed(in []byte) (point, error) {
+	return newKilicG1Point(bls12381.NewG1().FromBytes(in))
+}
+
+func (kilicG1) hashToCurve(msg, dst []byte) (point, error) {
+	return newKilicG1Point(bls12381.NewG1().HashToCurve(msg, dst))
+}
+
+func (kilicG1) mapToCurve(u []byte) (point, error) {
+	return newKilicG1Point(bls12381.NewG1().MapToCurve(u))
+}
+
+func (kilicG1) multiExp(ps []point, ss []*big.Int) point {
+	points := make([]*bls12381.PointG1, len(ps))
+	for i, p := range ps {
+		points[i] = kilicG1Of(p)
+	}
+	// the lengths always match, which is the only error
+	r, _ := bls12381.NewG1().MultiExpBig(new(bls12381.PointG1), points, ss)
+	return (*kilicG1Point)(r)
+}
+
+func (p *kilicG1Point) backend() Backend {
+	return BackendKilic
+}
+
+func (p *kilicG1Point) set(q point) {
+	(*bls12381.PointG1)(p).Set(kilicG1Of(q))
+}
+
+func (p *kilicG1Point) clone() point {
+	return (*kilicG1Point)(new(bls12381.PointG1).Set((*bls12381.PointG1)(p)))
+}
+
+func (p *kilicG1Point) add(a, b point) {
+	bls12381.NewG1().Add((*bls12381.PointG1)(p), kilicG1Of(a), kilicG1Of(b))
+}
+
+func (p *kilicG1Point) sub(a, b point) {
+	bls12381.NewG1().Sub((*bls12381.PointG1)(p), kilicG1Of(a), kilicG1Of(b))
+}
+
+func (p *kilicG1Point) neg(a point) {
+	bls12381.NewG1().Neg((*bls12381.PointG1)(p), kilicG1Of(a))
+}
+
+func (p *kilicG1Point) mul(a point, s *big.Int) {
+	bls12381.NewG1().MulScalarBig((*bls12381.PointG1)(p), kilicG1Of(a), s)
+}
+
+func (p *kilicG1Point) mulAny(a point, s *big.Int) {
+	g := bls12381.NewG1()
+	aa := kilicG1Of(a)
+	r := g.Zero()
+	for i := s.BitLen() - 1; i >= 0; i-- {
+		g.Double(r, r)
+		if s.Bit(i) == 1 {
+			g.Add(r, r, aa)
+		}
+	}
+	(*bls12381.PointG1)(p).Set(r)
+}
+
+func (p *kilicG1Point) equal(q point) bool {
+	return bls12381.NewG1().Equal((*bls12381.PointG1)(p), kilicG1Of(q))
+}
+
+func (p *kilicG1Point) isInfinity() bool {
+	return bls12381.NewG1().IsZero((*bls12381.PointG1)(p))
+}
+
+func (p *kilicG1Point) onCurve() bool {
+	return bls12381.NewG1().IsOnCurve((*bls12381.PointG1)(p))
+}
+
+func (p *kilicG1Point) inSubgroup() bool {
+	return bls12381.NewG1().InCorrectSubgroup((*bls12381.PointG1)(p))
+}
+
+func (p *kilicG1Point) clearCofactor() {
+	bls12381.NewG1().ClearCofactor((*bls12381.PointG1)(p))
+}
+
+func (p *kilicG1Point) compressed() []byte {
+	// we need to clone the point because of https://github.com/kilic/bls12-381/issues/37
+	// in order to avoid risks of race conditions.
+	t := new(bls12381.PointG1).Set((*bls12381.PointG1)(p))
+	return bls12381.NewG1().ToCompressed(t)
+}
+
+func (p *kilicG1Point) uncompressed() []byte {
+	t := new(bls12381.PointG1).Set((*bls12381.PointG1)(p))
+	return bls12381.NewG1().ToBytes(t)
+}
+
+type kilicG2 struct{}
+
+type kilicG2Point bls12381.PointG2
+
+func kilicG2Of(p point) *bls12381.PointG2 {
+	return (*bls12381.PointG2)(p.(*kilicG2Point))
+}
+
+func newKilicG2Point(p *bls12381.PointG2, err error) (point, error) {
+	if err != nil {
+		return nil, err
+	}
+	return (*kilicG2Point)(p), nil
+}
+
+func (kilicG2) zero() point {
+	return (*kilicG2Point)(bls12381.NewG2().Zero())
+}
+
+func (kilicG2) generator() point {
+	return (*kilicG2Point)(bls12381.NewG2().One())
+}
+
+func (kilicG2) fromCompressed(in []byte) (point, error) {
+	return newKilicG2Point(bls12381.NewG2().FromCompressed(in))
+}
+
+func (kilicG2) fromUncompressed(in []byte) (point, error) {
+	return newKilicG2Point(bls12381.NewG2().FromBytes(in))
+}
+
+func (kilicG2) hashToCurve(msg, dst []byte) (point, error) {
+	return newKilicG2Point(bls12381.NewG2().HashToCurve(msg, dst))
+}
+
+func (kilicG2) mapToCurve(u []byte) (point, error) {
+	return newKilicG2Point(bls12381.NewG2().MapToCurve(u))
+}
+
+func (kilicG2) multiExp(ps []point, ss []*big.Int) point {
+	points := make([]*bls12381.PointG2, len(ps))
+	for i, p := range ps {
+		points[i] = kilicG2Of(p)
+	}
+	// the lengths always match, which is the only error
+	r, _ := bls12381.NewG2().MultiExpBig(new(bls12381.PointG2), points, ss)
+	return (*kilicG2Point)(r)
+}
+
+func (p *kilicG2Point) backend() Backend {
+	return BackendKilic
+}
+
+func (p *kilicG2Point) set(q point) {
+	(*bls12381.PointG2)(p).Set(kilicG2Of(q))
+}
+
+func (p *kilicG2Point) clone() point {
+	return (*kilicG2Point)(new(bls12381.PointG2).Set((*bls12381.PointG2)(p)))
+}
+
+func (p *kilicG2Point) add(a, b point) {
+	bls12381.NewG2().Add((*bls12381.PointG2)(p), kilicG2Of(a), kilicG2Of(b))
+}
+
+func (p *kilicG2Point) sub(a, b point) {
+	bls12381.NewG2().Sub((*bls12381.PointG2)(p), kilicG2Of(a), kilicG2Of(b))
+}
+
+func (p *kilicG2Point) neg(a point) {
+	bls12381.NewG2().Neg((*bls12381.PointG2)(p), kilicG2Of(a))
+}
+
+func (p *kilicG2Point) mul(a point, s *big.Int) {
+	bls12381.NewG2().MulScalarBig((*bls12381.PointG2)(p), kilicG2Of(a), s)
+}
+
+func (p *kilicG2Point) mulAny(a point, s *big.Int) {
+	g := bls12381.NewG2()
+	aa := kilicG2Of(a)
+	r := g.Zero()
+	for i := s.BitLen() - 1; i >= 0; i-- {
+		g.Double(r, r)
+		if s.Bit(i) == 1 {
+			g.Add(r, r, aa)
+		}
+	}
+	(*bls12381.PointG2)(p).Set(r)
+}
+
+func (p *kilicG2Point) equal(q point) bool {
+	return bls12381.NewG2().Equal((*bls12381.PointG2)(p), kilicG2Of(q))
+}
+
+func (p *kilicG2Point) isInfinity() bool {
+	return bls12381.NewG2().IsZero((*bls12381.PointG2)(p))
+}
+
+func (p *kilicG2Point) onCurve() bool {
+	return bls12381.NewG2().IsOnCurve((*bls12381.PointG2)(p))
+}
+
+func (p *kilicG2Point) inSubgroup() bool {
+	return bls12381.NewG2().InCorrectSubgroup((*bls12381.PointG2)(p))
+}
+
+func (p *kilicG2Point) clearCofactor() {
+	bls12381.NewG2().ClearCofactor((*bls12381.PointG2)(p))
+}
+
+func (p *kilicG2Point) compressed() []byte {
+	// we need to clone the point because of https://github.com/kilic/bls12-381/issues/37
+	// in order to avoid risks of race conditions.
+	t := new(bls12381.PointG2).Set((*bls12381.PointG2)(p))
+	return bls12381.NewG2().ToCompressed(t)
+}
+
+func (p *kilicG2Point) uncompressed() []byte {
+	t := new(bls12381.PointG2).Set((*bls12381.PointG2)(p))
+	return bls12381.NewG2().ToBytes(t)
+}
+
+type kilicGT bls12381.E
+
+func kilicGTOf(e gtElement) *bls12381.E {
+	return (*bls12381.E)(e.(*kilicGT))
+}
+
+func (e *kilicGT) backend() Backend {
+	return BackendKilic
+}
+
+func (e *kilicGT) set(q gtElement) {
+	(*bls12381.E)(e).Set(kilicGTOf(q))
+}
+
+func (e *kilicGT) mul(a, b gtElement) {
+	bls12381.NewGT().Mul((*bls12381.E)(e), kilicGTOf(a), kilicGTOf(b))
+}
+
+func (e *kilicGT) inverse(a gtElement) {
+	bls12381.NewGT().Inverse((*bls12381.E)(e), kilicGTOf(a))
+}
+
+func (e *kilicGT) exp(a gtElement, s *big.Int) {
+	bls12381.NewGT().Exp((*bls12381.E)(e), kilicGTOf(a), s)
+}
+
+func (e *kilicGT) equal(q gtElement) bool {
+	return (*bls12381.E)(e).Equal(kilicGTOf(q))
+}
+
+func (e *kilicGT) inSubgroup() bool {
+	return bls12381.NewGT().IsValid((*bls12381.E)(e))
+}
+
+func (e *kilicGT) bytes() []byte {
+	return bls12381.NewGT().ToBytes((*bls12381.E)(e))
+}
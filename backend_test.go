@@ -0,0 +1,165 @@
+package bls
+
+import (
+	"testing"
+
+	"github.com/drand/kyber"
+	"github.com/drand/kyber/pairing"
+	"github.com/drand/kyber/sign/bls"
+	"github.com/drand/kyber/sign/tbls"
+	"github.com/drand/kyber/sign/test"
+	"github.com/drand/kyber/util/random"
+	"github.com/stretchr/testify/require"
+)
+
+var backends = []Backend{BackendKilic, BackendGnark}
+
+func TestBackendGroups(t *testing.T) {
+	for _, b := range backends {
+		t.Run(b.String(), func(t *testing.T) {
+			suite := NewBLS12381Suite(WithBackend(b))
+			require.Equal(t, b, suite.(*Suite).Backend())
+			GroupTest(t, suite.G1())
+			GroupTest(t, suite.G2())
+			test.SchemeTesting(t, bls.NewSchemeOnG1(suite))
+			test.SchemeTesting(t, bls.NewSchemeOnG2(suite))
+			test.ThresholdTest(t, suite.G1(), tbls.NewThresholdSchemeOnG2(suite))
+		})
+	}
+}
+
+func TestBackendPairing(t *testing.T) {
+	for _, b := range backends {
+		t.Run(b.String(), func(t *testing.T) {
+			s := NewBLS12381Suite(WithBackend(b)).(*Suite)
+			a := s.G1().Scalar().Pick(s.RandomStream())
+			c := s.G2().Scalar().Pick(s.RandomStream())
+			aG := s.G1().Point().Mul(a, nil)
+			cH := s.G2().Point().Mul(c, nil)
+			acG := s.G1().Point().Mul(s.G1().Scalar().Mul(a, c), nil)
+			require.True(t, s.Pair(aG, cH).Equal(s.Pair(acG, s.G2().Point().Base())))
+			require.True(t, s.ValidatePairing(aG, cH, acG, s.G2().Point().Base()))
+			require.False(t, s.ValidatePairing(aG, cH, aG, s.G2().Point().Base()))
+			e := s.Pair(aG, cH)
+			require.True(t, e.(GroupChecker).IsInCorrectGroup())
+			buf, err := e.MarshalBinary()
+			require.NoError(t, err)
+			f := s.GT().Point()
+			require.NoError(t, f.UnmarshalBinary(buf))
+			require.True(t, e.Equal(f))
+		})
+	}
+}
+
+func TestPairingCheck(t *testing.T) {
+	for _, b := range backends {
+		t.Run(b.String(), func(t *testing.T) {
+			s := NewBLS12381Suite(WithBackend(b)).(*Suite)
+			a := s.G1().Scalar().Pick(s.RandomStream())
+			c := s.G2().Scalar().Pick(s.RandomStream())
+			g1, g2 := s.G1().Point().Base(), s.G2().Point().Base()
+			// e(aG, cH) e(-acG, H) e(0, H) = 1
+			ps := []kyber.Point{
+				s.G1().Point().Mul(a, nil),
+				s.G1().Point().Neg(s.G1().Point().Mul(s.G1().Scalar().Mul(a, c), nil)),
+				s.G1().Point().Null(),
+			}
+			qs := []kyber.Point{s.G2().Point().Mul(c, nil), g2, g2}
+			require.True(t, s.PairingCheck(ps, qs))
+			require.True(t, s.PairingCheck(ps[2:], qs[2:]))
+			require.True(t, s.PairingCheck(nil, nil))
+			ps[2] = g1
+			require.False(t, s.PairingCheck(ps, qs))
+			require.Panics(t, func() { s.PairingCheck(ps, qs[:2]) })
+		})
+	}
+}
+
+// pairOnly hides the PairingCheck method of the suite, for CheckPairings to fall back to Pair.
+type pairOnly struct {
+	pairing.Suite
+}
+
+func TestCheckPairings(t *testing.T) {
+	s := NewBLS12381Suite()
+	a := s.G1().Scalar().Pick(s.RandomStream())
+	g1, g2 := s.G1().Point().Base(), s.G2().Point().Base()
+	// e(aG, H) e(-G, aH) = 1
+	ps := []kyber.Point{s.G1().Point().Mul(a, nil), s.G1().Point().Neg(g1)}
+	qs := []kyber.Point{g2, s.G2().Point().Mul(a, nil)}
+	for _, suite := range []pairing.Suite{s, pairOnly{s}} {
+		require.True(t, CheckPairings(suite, ps, qs))
+		require.True(t, CheckPairings(suite, nil, nil))
+		require.False(t, CheckPairings(suite, ps[:1], qs[:1]))
+		require.Panics(t, func() { CheckPairings(suite, ps, qs[:1]) })
+	}
+}
+
+// Both backends must give the same encodings, so that their points can be exchanged.
+func TestBackendsAgree(t *testing.T) {
+	kilic := NewBLS12381Suite(WithBackend(BackendKilic))
+	gnark := NewBLS12381Suite(WithBackend(BackendGnark))
+	rand := random.New()
+	for i := 0; i < 5; i++ {
+		msg := []byte{byte(i), 'm', 's', 'g'}
+		s := kilic.G1().Scalar().Pick(rand)
+
+		k1 := kilic.G1().Point().Mul(s, kilic.G1().Point().(*KyberG1).Hash(msg))
+		g1 := gnark.G1().Point().Mul(s, gnark.G1().Point().(*KyberG1).Hash(msg))
+		requireSamePoint(t, k1, g1, "G1 hash of %x", msg)
+		k2 := kilic.G2().Point().Mul(s, kilic.G2().Point().(*KyberG2).Hash(msg))
+		g2 := gnark.G2().Point().Mul(s, gnark.G2().Point().(*KyberG2).Hash(msg))
+		requireSamePoint(t, k2, g2, "G2 hash of %x", msg)
+		requireSamePoint(t, kilic.Pair(k1, k2), gnark.Pair(g1, g2), "pairing of %x", msg)
+
+		// points of different backends can be mixed
+		require.True(t, k1.Equal(g1))
+		require.True(t, g2.Equal(k2))
+		requireSamePoint(t, kilic.G1().Point().Add(k1, g1), gnark.G1().Point().Add(g1, k1), "addition")
+		require.True(t, kilic.G2().Point().Sub(k2, g2).Equal(gnark.G2().Point().Null()))
+		require.True(t, gnark.ValidatePairing(k1, g2, g1, k2))
+	}
+
+	// signatures are exchanged between backends
+	sk, pk := bls.NewSchemeOnG2(gnark).NewKeyPair(rand)
+	sig, err := bls.NewSchemeOnG2(gnark).Sign(sk, []byte("msg"))
+	require.NoError(t, err)
+	buf, err := pk.MarshalBinary()
+	require.NoError(t, err)
+	pk2 := kilic.G1().Point()
+	require.NoError(t, pk2.UnmarshalBinary(buf))
+	require.NoError(t, bls.NewSchemeOnG2(kilic).Verify(pk2, []byte("msg"), sig))
+}
+
+// Points outside of the subgroup, as returned by MapToCurveG1 and MapToCurveG2, are supported by all backends.
+func TestBackendsOutsideSubgroup(t *testing.T) {
+	u, err := HashToFieldFp([]byte("msg"), domainG1, 1, nil)
+	require.NoError(t, err)
+	q1, err := MapToCurveG1(u[0])
+	require.NoError(t, err)
+	v, err := HashToFieldFp2([]byte("msg"), domainG2, 1, nil)
+	require.NoError(t, err)
+	q2, err := MapToCurveG2(v[0])
+	require.NoError(t, err)
+	require.False(t, q1.IsInCorrectGroup())
+	require.False(t, q2.IsInCorrectGroup())
+
+	s := NewGroupG1().Scalar().Pick(random.New())
+	for _, b := range backends {
+		t.Run(b.String(), func(t *testing.T) {
+			// Set keeps track of the points which may be outside of the subgroup, see KyberG1.outside
+			p1 := newKyberG1(b.g1().zero(), nil, nil).Set(q1).(*KyberG1)
+			p2 := newKyberG2(b.g2().zero(), nil, nil).Set(q2).(*KyberG2)
+			require.False(t, p1.IsInCorrectGroup())
+			require.False(t, p2.IsInCorrectGroup())
+			requireSamePoint(t, q1.Clone().Mul(s, q1), p1.Clone().Mul(s, p1), "G1 multiplication")
+			requireSamePoint(t, q2.Clone().Mul(s, q2), p2.Clone().Mul(s, p2), "G2 multiplication")
+			c1 := p1.Clone().(*KyberG1).ClearCofactor(p1)
+			c2 := p2.Clone().(*KyberG2).ClearCofactor(p2)
+			require.True(t, c1.(GroupChecker).IsInCorrectGroup())
+			require.True(t, c2.(GroupChecker).IsInCorrectGroup())
+			requireSamePoint(t, NullKyberG1().ClearCofactor(q1), c1, "G1 cofactor clearing")
+			requireSamePoint(t, NullKyberG2().ClearCofactor(q2), c2, "G2 cofactor clearing")
+		})
+	}
+}
@@ -0,0 +1,99 @@
+// Package blind implements blind BLS signatures on BLS12-381: the signer signs a message without learning
+// it, nor being able to link the signature to the signing request, e.g. to issue privacy-preserving tokens.
+//
+// The user blinds the hash of the message with a random factor r, sending rH(m) to the signer, who returns
+// x rH(m) with its secret key x. The user unblinds it to the BLS signature xH(m), multiplying it by 1/r. The
+// messages are hashed with the DSTs of the suite, so that the signatures are the ones of kyber's sign/bls,
+// and verify with it.
+package blind
+
+import (
+	"crypto/cipher"
+	"errors"
+
+	"github.com/drand/kyber"
+	bls "github.com/drand/kyber-bls12381"
+	"github.com/drand/kyber/pairing"
+	"github.com/drand/kyber/sign"
+	kbls "github.com/drand/kyber/sign/bls"
+)
+
+var (
+	errGroup  = errors.New("blind: the point is not in the group of the signatures")
+	errFactor = errors.New("blind: the blinding factor is zero")
+)
+
+// Scheme is the blind BLS scheme with its signatures in one group of BLS12-381, and its public keys in the
+// other.
+type Scheme struct {
+	// sigs is the group of the signatures
+	sigs kyber.Group
+	// onG1 is set when the signatures are on G1
+	onG1 bool
+	// scheme verifies the signatures
+	scheme sign.AggregatableScheme
+}
+
+// NewSchemeOnG1 returns the scheme with its signatures on G1 and its public keys on G2, whose signatures are
+// the ones of the scheme of kbls.NewSchemeOnG1.
+func NewSchemeOnG1(suite pairing.Suite) *Scheme {
+	return &Scheme{sigs: suite.G1(), onG1: true, scheme: kbls.NewSchemeOnG1(suite)}
+}
+
+// NewSchemeOnG2 returns the scheme with its signatures on G2 and its public keys on G1, whose signatures are
+// the ones of the scheme of kbls.NewSchemeOnG2.
+func NewSchemeOnG2(suite pairing.Suite) *Scheme {
+	return &Scheme{sigs: suite.G2(), scheme: kbls.NewSchemeOnG2(suite)}
+}
+
+// Blind returns the hash of msg blinded by a random factor picked from rand, which the user keeps to unblind
+// the signature.
+func (s *Scheme) Blind(msg []byte, rand cipher.Stream) (blinded kyber.Point, factor kyber.Scalar, err error) {
+	h, ok := s.sigs.Point().(kyber.HashablePoint)
+	if !ok {
+		return nil, nil, errors.New("blind: point needs to implement hashablePoint")
+	}
+	factor = s.sigs.Scalar().Pick(rand)
+	for factor.Equal(s.sigs.Scalar().Zero()) {
+		factor.Pick(rand)
+	}
+	blinded = h.Hash(msg)
+	return blinded.Mul(factor, blinded), factor, nil
+}
+
+// BlindSign returns the blinded signature of the blinded hash with the secret key. The blinded hash must be
+// a point of the prime order subgroup of the group of the signatures other than the identity, so that the
+// signer does not sign points which would leak information about its key.
+func (s *Scheme) BlindSign(secret kyber.Scalar, blinded kyber.Point) (kyber.Point, error) {
+	if !s.inGroup(blinded) || blinded.Equal(s.sigs.Point().Null()) {
+		return nil, errGroup
+	}
+	return s.sigs.Point().Mul(secret, blinded), nil
+}
+
+// Unblind returns the encoding of the signature from the blinded signature and the blinding factor.
+func (s *Scheme) Unblind(blindSig kyber.Point, factor kyber.Scalar) ([]byte, error) {
+	if !s.inGroup(blindSig) {
+		return nil, errGroup
+	}
+	if factor.Equal(s.sigs.Scalar().Zero()) {
+		return nil, errFactor
+	}
+	inv := s.sigs.Scalar().Inv(factor)
+	return s.sigs.Point().Mul(inv, blindSig).MarshalBinary()
+}
+
+// Verify checks the signature of msg for the public key, as the Verify method of the BLS scheme of kyber.
+func (s *Scheme) Verify(public kyber.Point, msg, sig []byte) error {
+	return s.scheme.Verify(public, msg, sig)
+}
+
+// inGroup returns whether p is a point of the prime order subgroup of the group of the signatures.
+func (s *Scheme) inGroup(p kyber.Point) bool {
+	if s.onG1 {
+		q, ok := p.(*bls.KyberG1)
+		return ok && q.IsInCorrectGroup()
+	}
+	q, ok := p.(*bls.KyberG2)
+	return ok && q.IsInCorrectGroup()
+}
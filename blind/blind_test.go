@@ -0,0 +1,110 @@
+package blind
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/drand/kyber"
+	bls "github.com/drand/kyber-bls12381"
+	"github.com/drand/kyber/pairing"
+	"github.com/drand/kyber/sign"
+	kbls "github.com/drand/kyber/sign/bls"
+	"github.com/drand/kyber/util/random"
+	"github.com/stretchr/testify/require"
+)
+
+var backends = []bls.Backend{bls.BackendKilic, bls.BackendGnark}
+
+var msg = []byte("token")
+
+// schemes returns the schemes of the package along with the BLS schemes of kyber.
+func schemes(suite pairing.Suite) map[string]struct {
+	s      *Scheme
+	scheme sign.AggregatableScheme
+} {
+	return map[string]struct {
+		s      *Scheme
+		scheme sign.AggregatableScheme
+	}{
+		"G1": {NewSchemeOnG1(suite), kbls.NewSchemeOnG1(suite)},
+		"G2": {NewSchemeOnG2(suite), kbls.NewSchemeOnG2(suite)},
+	}
+}
+
+func TestBlind(t *testing.T) {
+	for _, b := range backends {
+		suites := map[string]pairing.Suite{
+			"":        bls.NewBLS12381Suite(bls.WithBackend(b)),
+			"/strict": bls.NewBLS12381Suite(bls.WithBackend(b), bls.WithStrictDomains()),
+		}
+		for suffix, suite := range suites {
+			for name, s := range schemes(suite) {
+				t.Run(b.String()+"/"+name+suffix, func(t *testing.T) {
+					secret, public := s.scheme.NewKeyPair(random.New())
+					blinded, factor, err := s.s.Blind(msg, random.New())
+					require.NoError(t, err)
+
+					// the signer receives the encoding of the blinded hash
+					buf, err := blinded.MarshalBinary()
+					require.NoError(t, err)
+					received := s.s.sigs.Point()
+					require.NoError(t, received.UnmarshalBinary(buf))
+					blindSig, err := s.s.BlindSign(secret, received)
+					require.NoError(t, err)
+
+					sig, err := s.s.Unblind(blindSig, factor)
+					require.NoError(t, err)
+					require.NoError(t, s.s.Verify(public, msg, sig))
+					require.NoError(t, s.scheme.Verify(public, msg, sig))
+					expected, err := s.scheme.Sign(secret, msg)
+					require.NoError(t, err)
+					require.Equal(t, expected, sig)
+
+					// the blinded hashes of the same message are unlinkable
+					other, _, err := s.s.Blind(msg, random.New())
+					require.NoError(t, err)
+					require.False(t, other.Equal(blinded))
+					require.NotEqual(t, expected, buf)
+
+					require.Error(t, s.s.Verify(public, []byte("other"), sig))
+					wrong, err := s.s.Unblind(blindSig, s.s.sigs.Scalar().Pick(random.New()))
+					require.NoError(t, err)
+					require.Error(t, s.s.Verify(public, msg, wrong))
+				})
+			}
+		}
+	}
+}
+
+func TestBlindSignErrors(t *testing.T) {
+	suite := bls.NewBLS12381Suite()
+	outside := map[string]kyber.Point{}
+	p1, err := bls.MapToCurveG1(big.NewInt(42))
+	require.NoError(t, err)
+	require.False(t, p1.IsInCorrectGroup())
+	outside["G1"] = p1
+	p2, err := bls.MapToCurveG2(&bls.Fp2{C0: big.NewInt(42), C1: big.NewInt(7)})
+	require.NoError(t, err)
+	require.False(t, p2.IsInCorrectGroup())
+	outside["G2"] = p2
+
+	for name, s := range schemes(suite) {
+		secret, _ := s.scheme.NewKeyPair(random.New())
+		_, err := s.s.BlindSign(secret, outside[name])
+		require.Error(t, err, name)
+		_, err = s.s.BlindSign(secret, s.s.sigs.Point().Null())
+		require.Error(t, err, name)
+		_, err = s.s.BlindSign(secret, suite.Pair(suite.G1().Point().Base(), suite.G2().Point().Base()))
+		require.Error(t, err, name)
+
+		blinded, _, err := s.s.Blind(msg, random.New())
+		require.NoError(t, err)
+		_, err = s.s.Unblind(blinded, s.s.sigs.Scalar().Zero())
+		require.Error(t, err, name)
+		_, err = s.s.Unblind(outside[name], s.s.sigs.Scalar().One())
+		require.Error(t, err, name)
+	}
+	// a point of the other group
+	_, err = NewSchemeOnG1(suite).BlindSign(suite.G1().Scalar().One(), suite.G2().Point().Base())
+	require.Error(t, err)
+}
@@ -0,0 +1,333 @@
+package bls
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/drand/kyber"
+	"github.com/drand/kyber/group/mod"
+)
+
+var (
+	// ErrWrongType is returned by the checked operations when an operand is not of the expected type, e.g. a
+	// G2 point given to a G1 operation or a scalar of another group.
+	ErrWrongType = errors.New("bls12-381: operand of the wrong type")
+	// ErrDomainMismatch is returned by the checked operations when points hashing to the curve with different
+	// DSTs or expanders are combined.
+	ErrDomainMismatch = errors.New("bls12-381: operands with different domain separation tags")
+	// ErrNoPoint is returned by the checked operations when an operand does not hold a point, e.g. a zero
+	// value KyberG1{}.
+	ErrNoPoint = errors.New("bls12-381: operand does not hold a point")
+)
+
+// CheckedPoint is implemented by KyberG1, KyberG2 and KyberGT. Its methods perform the operations of
+// kyber.Point of the same name after checking the operands, returning an error where kyber.Point panics or
+// silently mixes domains. The receiver is left unchanged when an error is returned.
+type CheckedPoint interface {
+	kyber.Point
+	SafeSet(q kyber.Point) (kyber.Point, error)
+	SafeAdd(a, b kyber.Point) (kyber.Point, error)
+	SafeSub(a, b kyber.Point) (kyber.Point, error)
+	SafeNeg(q kyber.Point) (kyber.Point, error)
+	SafeMul(s kyber.Scalar, q kyber.Point) (kyber.Point, error)
+}
+
+// checkScalar returns the value of s if it is a scalar modulo the order of the groups.
+func checkScalar(s kyber.Scalar) (*big.Int, error) {
+	ss, ok := s.(*mod.Int)
+	if !ok || ss == nil {
+		return nil, fmt.Errorf("%w: %T is not a BLS12-381 scalar", ErrWrongType, s)
+	}
+	if ss.M == nil || ss.M.Cmp(curveOrder) != 0 {
+		return nil, fmt.Errorf("%w: scalar is not modulo the order of BLS12-381", ErrWrongType)
+	}
+	if ss.V.Sign() < 0 || ss.V.Cmp(curveOrder) >= 0 {
+		return nil, fmt.Errorf("%w: scalar is not reduced", ErrWrongType)
+	}
+	return &ss.V, nil
+}
+
+// checkDomain returns an error if points hashing with the DSTs a and b, and the expanders ea and eb, would
+// be mixed.
+func checkDomain(a, b []byte, ea, eb Expander) error {
+	if !bytes.Equal(a, b) || ea != eb {
+		return fmt.Errorf("%w: %q and %q", ErrDomainMismatch, a, b)
+	}
+	return nil
+}
+
+// asG1 returns q if it is a G1 point holding a point.
+func asG1(q kyber.Point) (*KyberG1, error) {
+	qq, ok := q.(*KyberG1)
+	if !ok || qq == nil {
+		return nil, fmt.Errorf("%w: %T is not a G1 point", ErrWrongType, q)
+	}
+	if qq.p == nil {
+		return nil, ErrNoPoint
+	}
+	return qq, nil
+}
+
+// check returns q if it is a G1 point with the domain of k.
+func (k *KyberG1) check(q kyber.Point) (*KyberG1, error) {
+	qq, err := asG1(q)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkDomain(k.domain(), qq.domain(), k.exp, qq.exp); err != nil {
+		return nil, err
+	}
+	return qq, nil
+}
+
+// SafeSet is Set, checking that q is a G1 point. Like Set, it copies the domain of q.
+func (k *KyberG1) SafeSet(q kyber.Point) (kyber.Point, error) {
+	if _, err := asG1(q); err != nil {
+		return nil, err
+	}
+	return k.Set(q), nil
+}
+
+// SafeAdd is Add, checking that a and b are G1 points with the same domain as k.
+func (k *KyberG1) SafeAdd(a, b kyber.Point) (kyber.Point, error) {
+	if err := k.checkAll(a, b); err != nil {
+		return nil, err
+	}
+	return k.Add(a, b), nil
+}
+
+// SafeSub is Sub, checking that a and b are G1 points with the same domain as k.
+func (k *KyberG1) SafeSub(a, b kyber.Point) (kyber.Point, error) {
+	if err := k.checkAll(a, b); err != nil {
+		return nil, err
+	}
+	return k.Sub(a, b), nil
+}
+
+// SafeNeg is Neg, checking that q is a G1 point with the same domain as k.
+func (k *KyberG1) SafeNeg(q kyber.Point) (kyber.Point, error) {
+	if _, err := k.check(q); err != nil {
+		return nil, err
+	}
+	return k.Neg(q), nil
+}
+
+// SafeMul is Mul, checking that s is a scalar of the group and that q is nil or a G1 point with the same
+// domain as k.
+func (k *KyberG1) SafeMul(s kyber.Scalar, q kyber.Point) (kyber.Point, error) {
+	if _, err := checkScalar(s); err != nil {
+		return nil, err
+	}
+	if q != nil {
+		if _, err := k.check(q); err != nil {
+			return nil, err
+		}
+	}
+	return k.Mul(s, q), nil
+}
+
+func (k *KyberG1) checkAll(ps ...kyber.Point) error {
+	for _, p := range ps {
+		if _, err := k.check(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// asG2 returns q if it is a G2 point holding a point.
+func asG2(q kyber.Point) (*KyberG2, error) {
+	qq, ok := q.(*KyberG2)
+	if !ok || qq == nil {
+		return nil, fmt.Errorf("%w: %T is not a G2 point", ErrWrongType, q)
+	}
+	if qq.p == nil {
+		return nil, ErrNoPoint
+	}
+	return qq, nil
+}
+
+// check returns q if it is a G2 point with the domain of k.
+func (k *KyberG2) check(q kyber.Point) (*KyberG2, error) {
+	qq, err := asG2(q)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkDomain(k.domain(), qq.domain(), k.exp, qq.exp); err != nil {
+		return nil, err
+	}
+	return qq, nil
+}
+
+// SafeSet is Set, checking that q is a G2 point. Like Set, it copies the domain of q.
+func (k *KyberG2) SafeSet(q kyber.Point) (kyber.Point, error) {
+	if _, err := asG2(q); err != nil {
+		return nil, err
+	}
+	return k.Set(q), nil
+}
+
+// SafeAdd is Add, checking that a and b are G2 points with the same domain as k.
+func (k *KyberG2) SafeAdd(a, b kyber.Point) (kyber.Point, error) {
+	if err := k.checkAll(a, b); err != nil {
+		return nil, err
+	}
+	return k.Add(a, b), nil
+}
+
+// SafeSub is Sub, checking that a and b are G2 points with the same domain as k.
+func (k *KyberG2) SafeSub(a, b kyber.Point) (kyber.Point, error) {
+	if err := k.checkAll(a, b); err != nil {
+		return nil, err
+	}
+	return k.Sub(a, b), nil
+}
+
+// SafeNeg is Neg, checking that q is a G2 point with the same domain as k.
+func (k *KyberG2) SafeNeg(q kyber.Point) (kyber.Point, error) {
+	if _, err := k.check(q); err != nil {
+		return nil, err
+	}
+	return k.Neg(q), nil
+}
+
+// SafeMul is Mul, checking that s is a scalar of the group and that q is nil or a G2 point with the same
+// domain as k.
+func (k *KyberG2) SafeMul(s kyber.Scalar, q kyber.Point) (kyber.Point, error) {
+	if _, err := checkScalar(s); err != nil {
+		return nil, err
+	}
+	if q != nil {
+		if _, err := k.check(q); err != nil {
+			return nil, err
+		}
+	}
+	return k.Mul(s, q), nil
+}
+
+func (k *KyberG2) checkAll(ps ...kyber.Point) error {
+	for _, p := range ps {
+		if _, err := k.check(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// check returns q if it is an element of GT.
+func (k *KyberGT) check(q kyber.Point) (*KyberGT, error) {
+	qq, ok := q.(*KyberGT)
+	if !ok || qq == nil {
+		return nil, fmt.Errorf("%w: %T is not a GT element", ErrWrongType, q)
+	}
+	if qq.f == nil {
+		return nil, ErrNoPoint
+	}
+	return qq, nil
+}
+
+// SafeSet is Set, checking that q is an element of GT.
+func (k *KyberGT) SafeSet(q kyber.Point) (kyber.Point, error) {
+	if _, err := k.check(q); err != nil {
+		return nil, err
+	}
+	return k.setIfEmpty().Set(q), nil
+}
+
+// SafeAdd is Add, checking that a and b are elements of GT.
+func (k *KyberGT) SafeAdd(a, b kyber.Point) (kyber.Point, error) {
+	if err := k.checkAll(a, b); err != nil {
+		return nil, err
+	}
+	return k.setIfEmpty().Add(a, b), nil
+}
+
+// SafeSub is Sub, checking that a and b are elements of GT.
+func (k *KyberGT) SafeSub(a, b kyber.Point) (kyber.Point, error) {
+	if err := k.checkAll(a, b); err != nil {
+		return nil, err
+	}
+	return k.setIfEmpty().Sub(a, b), nil
+}
+
+// SafeNeg is Neg, checking that q is an element of GT.
+func (k *KyberGT) SafeNeg(q kyber.Point) (kyber.Point, error) {
+	if _, err := k.check(q); err != nil {
+		return nil, err
+	}
+	return k.setIfEmpty().Neg(q), nil
+}
+
+// SafeMul is Mul, checking that s is a scalar of the group and q an element of GT. Unlike for G1 and G2,
+// q cannot be nil since GT has no base.
+func (k *KyberGT) SafeMul(s kyber.Scalar, q kyber.Point) (kyber.Point, error) {
+	if _, err := checkScalar(s); err != nil {
+		return nil, err
+	}
+	if _, err := k.check(q); err != nil {
+		return nil, err
+	}
+	return k.setIfEmpty().Mul(s, q), nil
+}
+
+func (k *KyberGT) checkAll(ps ...kyber.Point) error {
+	for _, p := range ps {
+		if _, err := k.check(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setIfEmpty sets k to one if it does not hold an element.
+func (k *KyberGT) setIfEmpty() *KyberGT {
+	if k.f == nil {
+		k.backend = backendOrDefault(k.backend)
+		k.f = k.backend.gtOne()
+	}
+	return k
+}
+
+// SafePair is Pair, checking that p1 is a G1 point and p2 a G2 point, with the domains of the suite if it has
+// strict domains.
+func (s *Suite) SafePair(p1, p2 kyber.Point) (kyber.Point, error) {
+	if err := checkPairing(p1, p2); err != nil {
+		return nil, err
+	}
+	if err := s.checkDomains([]kyber.Point{p1}, []kyber.Point{p2}); err != nil {
+		return nil, err
+	}
+	return s.Pair(p1, p2), nil
+}
+
+// SafeValidatePairing is ValidatePairing, checking that p1 and p3 are G1 points with the same domain, and p2
+// and p4 G2 points with the same domain, the domains of the suite if it has strict domains.
+func (s *Suite) SafeValidatePairing(p1, p2, p3, p4 kyber.Point) (bool, error) {
+	if err := checkPairing(p1, p2); err != nil {
+		return false, err
+	}
+	if err := checkPairing(p3, p4); err != nil {
+		return false, err
+	}
+	if _, err := p1.(*KyberG1).check(p3); err != nil {
+		return false, err
+	}
+	if _, err := p2.(*KyberG2).check(p4); err != nil {
+		return false, err
+	}
+	if err := s.checkDomains([]kyber.Point{p1, p3}, []kyber.Point{p2, p4}); err != nil {
+		return false, err
+	}
+	return s.ValidatePairing(p1, p2, p3, p4), nil
+}
+
+// checkPairing checks that p1 is a G1 point and p2 a G2 point.
+func checkPairing(p1, p2 kyber.Point) error {
+	if _, err := asG1(p1); err != nil {
+		return err
+	}
+	_, err := asG2(p2)
+	return err
+}
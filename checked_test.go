@@ -0,0 +1,122 @@
+package bls
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/drand/kyber"
+	"github.com/drand/kyber/group/mod"
+	"github.com/drand/kyber/util/random"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckedPoints(t *testing.T) {
+	suite := NewBLS12381Suite().(*Suite)
+	other := NewBLS12381SuiteWithDST([]byte("other G1 DST"), []byte("other G2 DST")).(*Suite)
+	s := suite.G1().Scalar().Pick(random.New())
+	for _, tc := range []struct {
+		group, otherGroup, wrongGroup kyber.Group
+	}{
+		{suite.G1(), other.G1(), suite.G2()},
+		{suite.G2(), other.G2(), suite.GT()},
+		{suite.GT(), nil, suite.G1()},
+	} {
+		t.Run(tc.group.String(), func(t *testing.T) {
+			var a, b kyber.Point
+			if _, ok := tc.group.Point().(*KyberGT); ok {
+				a = suite.Pair(suite.G1().Point().Pick(random.New()), suite.G2().Point().Base())
+				b = suite.Pair(suite.G1().Point().Base(), suite.G2().Point().Pick(random.New()))
+			} else {
+				a, b = tc.group.Point().Pick(random.New()), tc.group.Point().Pick(random.New())
+			}
+			k := tc.group.Point().(CheckedPoint)
+
+			// the checked operations agree with the unchecked ones
+			r, err := k.SafeAdd(a, b)
+			require.NoError(t, err)
+			require.True(t, r.Equal(tc.group.Point().Add(a, b)))
+			r, err = k.SafeSub(a, b)
+			require.NoError(t, err)
+			require.True(t, r.Equal(tc.group.Point().Sub(a, b)))
+			// and set their receiver, as Sub
+			require.Same(t, k, r)
+			c := tc.group.Point()
+			require.Same(t, c, c.Sub(a, b))
+			require.True(t, c.Equal(r))
+			r, err = k.SafeNeg(a)
+			require.NoError(t, err)
+			require.True(t, r.Equal(tc.group.Point().Neg(a)))
+			r, err = k.SafeMul(s, a)
+			require.NoError(t, err)
+			require.True(t, r.Equal(tc.group.Point().Mul(s, a)))
+			r, err = k.SafeSet(b)
+			require.NoError(t, err)
+			require.True(t, r.Equal(b))
+
+			// operands of the wrong type
+			wrong := tc.wrongGroup.Point()
+			_, err = k.SafeAdd(a, wrong)
+			require.ErrorIs(t, err, ErrWrongType)
+			_, err = k.SafeSub(wrong, a)
+			require.ErrorIs(t, err, ErrWrongType)
+			_, err = k.SafeNeg(wrong)
+			require.ErrorIs(t, err, ErrWrongType)
+			_, err = k.SafeSet(nil)
+			require.ErrorIs(t, err, ErrWrongType)
+			_, err = k.SafeMul(mod.NewInt64(1, big.NewInt(7)), a)
+			require.ErrorIs(t, err, ErrWrongType)
+			_, err = k.SafeMul(nil, a)
+			require.ErrorIs(t, err, ErrWrongType)
+			_, err = k.SafeMul(s, wrong)
+			require.ErrorIs(t, err, ErrWrongType)
+
+			// operands without a point, such as zero values, and a failed decoding which keeps the point
+			empty := reflect.New(reflect.TypeOf(a).Elem()).Interface().(kyber.Point)
+			require.Error(t, b.UnmarshalBinary([]byte{1, 2, 3}))
+			_, err = k.SafeAdd(a, empty)
+			require.ErrorIs(t, err, ErrNoPoint)
+			// the receiver is unchanged by the failed operations
+			require.True(t, k.Equal(b))
+			// and an empty receiver is usable
+			r, err = empty.(CheckedPoint).SafeAdd(a, b)
+			require.NoError(t, err)
+			require.True(t, r.Equal(tc.group.Point().Add(a, b)))
+
+			// operands of another domain
+			if tc.otherGroup != nil {
+				c := tc.otherGroup.Point().Pick(random.New())
+				_, err = k.SafeAdd(a, c)
+				require.ErrorIs(t, err, ErrDomainMismatch)
+				_, err = k.SafeMul(s, c)
+				require.ErrorIs(t, err, ErrDomainMismatch)
+				// Set copies the domain
+				r, err = tc.otherGroup.Point().(CheckedPoint).SafeSet(a)
+				require.NoError(t, err)
+				require.True(t, r.Equal(a))
+			}
+		})
+	}
+}
+
+func TestCheckedPairing(t *testing.T) {
+	suite := NewBLS12381Suite().(*Suite)
+	other := NewBLS12381SuiteWithDST(nil, []byte("other G2 DST")).(*Suite)
+	p, q := suite.G1().Point().Pick(random.New()), suite.G2().Point().Pick(random.New())
+
+	e, err := suite.SafePair(p, q)
+	require.NoError(t, err)
+	require.True(t, e.Equal(suite.Pair(p, q)))
+	_, err = suite.SafePair(q, p)
+	require.ErrorIs(t, err, ErrWrongType)
+	_, err = suite.SafePair(p, nil)
+	require.ErrorIs(t, err, ErrWrongType)
+
+	ok, err := suite.SafeValidatePairing(p, q, p, q)
+	require.NoError(t, err)
+	require.True(t, ok)
+	_, err = suite.SafeValidatePairing(p, q, q, p)
+	require.ErrorIs(t, err, ErrWrongType)
+	_, err = suite.SafeValidatePairing(p, q, p, other.G2().Point().Pick(random.New()))
+	require.ErrorIs(t, err, ErrDomainMismatch)
+}
@@ -0,0 +1,25 @@
+//go:build ethspec
+
+package bls
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestEthereumBLSRelease runs the JSON release v0.1.1 of the ethereum/bls12-381-tests suite, which is not
+// checked in, extracted under the directory given by ETH_BLS_TESTS:
+//
+//	mkdir -p /tmp/bls12-381-tests
+//	curl -L https://github.com/ethereum/bls12-381-tests/releases/download/v0.1.1/bls_tests_json.tar.gz |
+//		tar -xz -C /tmp/bls12-381-tests
+//	ETH_BLS_TESTS=/tmp/bls12-381-tests go test -tags ethspec -run TestEthereumBLSRelease
+//
+// The handlers of the suite which are not implemented by ethHandlers, such as aggregate_verify, are skipped.
+func TestEthereumBLSRelease(t *testing.T) {
+	dir := os.Getenv("ETH_BLS_TESTS")
+	require.NotEmpty(t, dir, "ETH_BLS_TESTS must point to the extracted bls_tests_json.tar.gz")
+	require.NotZero(t, runEthVectors(t, dir))
+}
@@ -0,0 +1,325 @@
+package bls
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/drand/kyber"
+	"github.com/drand/kyber/sign"
+	"github.com/drand/kyber/sign/bls"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+// The conformance tests run the test vectors checked in under testdata, see testdata/README.md for their origin.
+
+func loadJSON(t *testing.T, path string, v interface{}) {
+	buf, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(buf, v))
+}
+
+func globVectors(t *testing.T, pattern string) []string {
+	files, err := filepath.Glob(filepath.Join("testdata", pattern))
+	require.NoError(t, err)
+	require.NotEmpty(t, files, "no test vectors matching %s", pattern)
+	return files
+}
+
+func decodeHex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	require.NoError(t, err)
+	return b
+}
+
+type h2cPoint struct {
+	X string `json:"x"`
+	Y string `json:"y"`
+}
+
+type h2cSuite struct {
+	Ciphersuite  string `json:"ciphersuite"`
+	Curve        string `json:"curve"`
+	Dst          string `json:"dst"`
+	RandomOracle bool   `json:"randomOracle"`
+	Vectors      []struct {
+		Msg string   `json:"msg"`
+		U   []string `json:"u"`
+		P   h2cPoint `json:"P"`
+		Q   h2cPoint `json:"Q"`
+		Q0  h2cPoint `json:"Q0"`
+		Q1  h2cPoint `json:"Q1"`
+	} `json:"vectors"`
+}
+
+func h2cFp2(s string) *Fp2 {
+	c := strings.Split(s, ",")
+	return fp2FromHex(strings.TrimPrefix(c[0], "0x"), strings.TrimPrefix(c[1], "0x"))
+}
+
+func (p h2cPoint) g1(t *testing.T) *KyberG1 {
+	return affineG1(t, strings.TrimPrefix(p.X, "0x"), strings.TrimPrefix(p.Y, "0x"))
+}
+
+func (p h2cPoint) g2(t *testing.T) *KyberG2 {
+	x, y := h2cFp2(p.X), h2cFp2(p.Y)
+	return affineG2(t, x.C0.Text(16), x.C1.Text(16), y.C0.Text(16), y.C1.Text(16))
+}
+
+// requireSamePoint compares points regardless of the DST they carry.
+func requireSamePoint(t *testing.T, exp, got kyber.Point, msgAndArgs ...interface{}) {
+	e, _ := exp.MarshalBinary()
+	g, _ := got.MarshalBinary()
+	require.Equal(t, e, g, msgAndArgs...)
+}
+
+// RFC 9380 appendix J: every stage of hash_to_curve and encode_to_curve is checked.
+func TestConformanceHashToCurve(t *testing.T) {
+	for _, file := range globVectors(t, "hash_to_curve/BLS12381*.json") {
+		var suite h2cSuite
+		loadJSON(t, file, &suite)
+		dst := []byte(suite.Dst)
+		t.Run(suite.Ciphersuite, func(t *testing.T) {
+			for _, v := range suite.Vectors {
+				msg := []byte(v.Msg)
+				switch suite.Curve {
+				case "BLS12-381 G1":
+					u, err := HashToFieldFp(msg, dst, len(v.U), nil)
+					require.NoError(t, err)
+					var q []*KyberG1
+					for i := range v.U {
+						require.Equal(t, 0, u[i].Cmp(fpFromHex(strings.TrimPrefix(v.U[i], "0x"))), "u[%d] of %q", i, v.Msg)
+						qi, err := MapToCurveG1(u[i])
+						require.NoError(t, err)
+						q = append(q, qi)
+					}
+					if suite.RandomOracle {
+						requireSamePoint(t, v.Q0.g1(t), q[0], "Q0 of %q", v.Msg)
+						requireSamePoint(t, v.Q1.g1(t), q[1], "Q1 of %q", v.Msg)
+						requireSamePoint(t, v.P.g1(t), NullKyberG1(dst...).Hash(msg), "P of %q", v.Msg)
+					} else {
+						requireSamePoint(t, v.Q.g1(t), q[0], "Q of %q", v.Msg)
+						requireSamePoint(t, v.P.g1(t), NullKyberG1().ClearCofactor(q[0]), "P of %q", v.Msg)
+					}
+				case "BLS12-381 G2":
+					u, err := HashToFieldFp2(msg, dst, len(v.U), nil)
+					require.NoError(t, err)
+					var q []*KyberG2
+					for i := range v.U {
+						require.True(t, u[i].equal(h2cFp2(v.U[i])), "u[%d] of %q", i, v.Msg)
+						qi, err := MapToCurveG2(u[i])
+						require.NoError(t, err)
+						q = append(q, qi)
+					}
+					if suite.RandomOracle {
+						requireSamePoint(t, v.Q0.g2(t), q[0], "Q0 of %q", v.Msg)
+						requireSamePoint(t, v.Q1.g2(t), q[1], "Q1 of %q", v.Msg)
+						requireSamePoint(t, v.P.g2(t), NullKyberG2(dst...).Hash(msg), "P of %q", v.Msg)
+					} else {
+						requireSamePoint(t, v.Q.g2(t), q[0], "Q of %q", v.Msg)
+						requireSamePoint(t, v.P.g2(t), NullKyberG2().ClearCofactor(q[0]), "P of %q", v.Msg)
+					}
+				default:
+					t.Fatalf("unknown curve %s", suite.Curve)
+				}
+			}
+		})
+	}
+}
+
+// RFC 9380 appendix K: expand_message_xmd and expand_message_xof.
+func TestConformanceExpandMessage(t *testing.T) {
+	expanders := map[string]Expander{
+		"SHA256":   ExpanderXMDSHA256,
+		"SHA512":   ExpanderXMDSHA512,
+		"SHAKE128": ExpanderXOFSHAKE128,
+		"SHAKE256": ExpanderXOFSHAKE256,
+	}
+	for _, file := range globVectors(t, "hash_to_curve/expand_message_*.json") {
+		var suite struct {
+			DST   string `json:"DST"`
+			Hash  string `json:"hash"`
+			Tests []struct {
+				LenInBytes   string `json:"len_in_bytes"`
+				Msg          string `json:"msg"`
+				UniformBytes string `json:"uniform_bytes"`
+			} `json:"tests"`
+		}
+		loadJSON(t, file, &suite)
+		exp, ok := expanders[suite.Hash]
+		require.True(t, ok, "unknown hash %s", suite.Hash)
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			for _, v := range suite.Tests {
+				expected := decodeHex(t, v.UniformBytes)
+				out, err := exp.ExpandMessage([]byte(v.Msg), []byte(suite.DST), len(expected))
+				require.NoError(t, err)
+				require.Equal(t, expected, out, "msg %q, len %s", v.Msg, v.LenInBytes)
+			}
+		})
+	}
+}
+
+// domainEth is the DST of the Ethereum proof of possession ciphersuite, with public keys on G1.
+var domainEth = []byte("BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_")
+
+type ethVector struct {
+	Input  json.RawMessage `json:"input"`
+	Output json.RawMessage `json:"output"`
+}
+
+// ethPublicKey implements the KeyValidate step of the specification: the key must decode to a point of G1
+// other than the identity.
+func ethPublicKey(t *testing.T, suite kyber.Group, s string) (kyber.Point, bool) {
+	pk := suite.Point()
+	if err := pk.UnmarshalBinary(decodeHex(t, s)); err != nil {
+		return nil, false
+	}
+	return pk, !pk.Equal(suite.Point().Null())
+}
+
+// ethHandlers returns the handlers of the ethereum/bls12-381-tests suite, which return the expected output of
+// the input of a test case.
+func ethHandlers() map[string]func(t *testing.T, in json.RawMessage) interface{} {
+	suite := NewBLS12381SuiteWithDST(nil, domainEth)
+	scheme := bls.NewSchemeOnG2(suite)
+	var _ sign.AggregatableScheme = scheme
+	return map[string]func(t *testing.T, in json.RawMessage) interface{}{
+		"sign": func(t *testing.T, in json.RawMessage) interface{} {
+			var input struct{ Privkey, Message string }
+			require.NoError(t, json.Unmarshal(in, &input))
+			sk := suite.G1().Scalar()
+			require.NoError(t, sk.UnmarshalBinary(decodeHex(t, input.Privkey)))
+			if sk.Equal(suite.G1().Scalar().Zero()) {
+				return nil
+			}
+			sig, err := scheme.Sign(sk, decodeHex(t, input.Message))
+			require.NoError(t, err)
+			return "0x" + hex.EncodeToString(sig)
+		},
+		"verify": func(t *testing.T, in json.RawMessage) interface{} {
+			var input struct{ Pubkey, Message, Signature string }
+			require.NoError(t, json.Unmarshal(in, &input))
+			pk, ok := ethPublicKey(t, suite.G1(), input.Pubkey)
+			if !ok {
+				return false
+			}
+			return scheme.Verify(pk, decodeHex(t, input.Message), decodeHex(t, input.Signature)) == nil
+		},
+		"aggregate": func(t *testing.T, in json.RawMessage) interface{} {
+			var input []string
+			require.NoError(t, json.Unmarshal(in, &input))
+			if len(input) == 0 {
+				return nil
+			}
+			var sigs [][]byte
+			for _, s := range input {
+				sigs = append(sigs, decodeHex(t, s))
+			}
+			agg, err := scheme.AggregateSignatures(sigs...)
+			if err != nil {
+				return nil
+			}
+			return "0x" + hex.EncodeToString(agg)
+		},
+		"fast_aggregate_verify": func(t *testing.T, in json.RawMessage) interface{} {
+			var input struct {
+				Pubkeys            []string
+				Message, Signature string
+			}
+			require.NoError(t, json.Unmarshal(in, &input))
+			if len(input.Pubkeys) == 0 {
+				return false
+			}
+			var pks []kyber.Point
+			for _, s := range input.Pubkeys {
+				pk, ok := ethPublicKey(t, suite.G1(), s)
+				if !ok {
+					return false
+				}
+				pks = append(pks, pk)
+			}
+			agg := scheme.AggregatePublicKeys(pks...)
+			return scheme.Verify(agg, decodeHex(t, input.Message), decodeHex(t, input.Signature)) == nil
+		},
+		"deserialization_G1": func(t *testing.T, in json.RawMessage) interface{} {
+			var input struct{ Pubkey string }
+			require.NoError(t, json.Unmarshal(in, &input))
+			return suite.G1().Point().UnmarshalBinary(decodeHex(t, input.Pubkey)) == nil
+		},
+		"deserialization_G2": func(t *testing.T, in json.RawMessage) interface{} {
+			var input struct{ Signature string }
+			require.NoError(t, json.Unmarshal(in, &input))
+			return suite.G2().Point().UnmarshalBinary(decodeHex(t, input.Signature)) == nil
+		},
+	}
+}
+
+// runEthVectors runs the test cases of the handlers found under dir, in <handler>/<case>.json or
+// <handler>/<case>.yaml, and returns their number.
+func runEthVectors(t *testing.T, dir string) int {
+	n := 0
+	for handler, run := range ethHandlers() {
+		var files []string
+		for _, ext := range []string{"*.json", "*.yaml"} {
+			matches, err := filepath.Glob(filepath.Join(dir, handler, ext))
+			require.NoError(t, err)
+			files = append(files, matches...)
+		}
+		if len(files) == 0 {
+			continue
+		}
+		t.Run(handler, func(t *testing.T) {
+			for _, file := range files {
+				v := loadEthVector(t, file)
+				// the YAML files write null for the encodings which must be rejected
+				if strings.HasPrefix(handler, "deserialization_") && string(v.Output) == "null" {
+					v.Output = json.RawMessage("false")
+				}
+				got, err := json.Marshal(run(t, v.Input))
+				require.NoError(t, err)
+				var exp bytes.Buffer
+				require.NoError(t, json.Compact(&exp, v.Output))
+				require.Equal(t, exp.String(), string(got), filepath.Base(file))
+			}
+		})
+		n += len(files)
+	}
+	return n
+}
+
+// loadEthVector reads a test case in JSON, or in YAML converted to JSON.
+func loadEthVector(t *testing.T, path string) ethVector {
+	var v ethVector
+	if filepath.Ext(path) == ".json" {
+		loadJSON(t, path, &v)
+		return v
+	}
+	buf, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var raw struct {
+		Input  interface{} `yaml:"input"`
+		Output interface{} `yaml:"output"`
+	}
+	require.NoError(t, yaml.Unmarshal(buf, &raw))
+	v.Input, err = json.Marshal(raw.Input)
+	require.NoError(t, err)
+	v.Output, err = json.Marshal(raw.Output)
+	require.NoError(t, err)
+	return v
+}
+
+// The deserialization tests of the Ethereum consensus specification, copied verbatim from the upstream suite.
+func TestConformanceEthereumBLS(t *testing.T) {
+	require.Equal(t, 34, runEthVectors(t, filepath.Join("testdata", "eth_bls")))
+}
+
+// Vectors in the format of the ethereum/bls12-381-tests suite with outputs computed by blst, for the handlers
+// which are not vendored from the upstream suite.
+func TestConformanceBlstPOP(t *testing.T) {
+	require.Equal(t, 79, runEthVectors(t, filepath.Join("testdata", "blst_pop")))
+}
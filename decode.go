@@ -0,0 +1,94 @@
+package bls
+
+import (
+	"errors"
+	"math/big"
+)
+
+// The errors returned when decoding a point or an element of GT, giving the reason why the encoding is
+// rejected. The backends do not report these reasons consistently, so the encodings are checked by
+// decodeCompressed and decodeGT before being decoded by the backend.
+var (
+	// ErrEncodingLength is returned for an encoding which does not have the size of the element.
+	ErrEncodingLength = errors.New("bls12-381: invalid encoding length")
+	// ErrCompressionFlag is returned for a point whose compression flag is not set.
+	ErrCompressionFlag = errors.New("bls12-381: compression flag not set")
+	// ErrInfinityEncoding is returned for a point with the infinity flag set along with other bits.
+	ErrInfinityEncoding = errors.New("bls12-381: invalid encoding of the point at infinity")
+	// ErrNonCanonical is returned for an encoding of a field element which is not reduced modulo p.
+	ErrNonCanonical = errors.New("bls12-381: field element not reduced modulo p")
+	// ErrNotOnCurve is returned for a compressed x coordinate with no point of the curve.
+	ErrNotOnCurve = errors.New("bls12-381: point is not on the curve")
+	// ErrNotInSubgroup is returned for a point or an element of Fp12 outside of the subgroup of order r.
+	ErrNotInSubgroup = errors.New("bls12-381: element is not in the subgroup of order r")
+)
+
+// decodeCompressed decodes a point of the subgroup of c in the ZCash compressed format, where size is 48
+// for E1 and 96 for E2.
+func decodeCompressed(c curve, in []byte, size int) (point, error) {
+	if len(in) != size {
+		return nil, ErrEncodingLength
+	}
+	flags := in[0] & 0xe0
+	if flags&0x80 == 0 {
+		return nil, ErrCompressionFlag
+	}
+	x := append([]byte(nil), in...)
+	x[0] &= 0x1f
+	if flags&0x40 != 0 {
+		if flags&0x20 != 0 || !allZero(x) {
+			return nil, ErrInfinityEncoding
+		}
+		return c.zero(), nil
+	}
+	coords := fpFromBytes(x)
+	if coords == nil {
+		return nil, ErrNonCanonical
+	}
+	p, err := c.fromCompressed(in)
+	if err == nil {
+		return p, nil
+	}
+	// the encoding is well formed, the backend rejects either x or the point
+	var onCurve bool
+	if size == fpByteSize {
+		onCurve = fpIsSquare(fpAdd(fpMul(fpMul(coords[0], coords[0]), coords[0]), big.NewInt(4)))
+	} else {
+		// x = c1 || c0, as by Fp2.Bytes
+		x := newFp2(coords[1], coords[0])
+		onCurve = fp2IsSquare(fp2Add(fp2Mul(fp2Square(x), x), newFp2(big.NewInt(4), big.NewInt(4))))
+	}
+	if !onCurve {
+		return nil, ErrNotOnCurve
+	}
+	return nil, ErrNotInSubgroup
+}
+
+// decodeGT decodes an element of GT from the encoding of its 12 coefficients in Fp.
+func decodeGT(b Backend, in []byte) (gtElement, error) {
+	if len(in) != 12*fpByteSize {
+		return nil, ErrEncodingLength
+	}
+	if fpFromBytes(in) == nil {
+		return nil, ErrNonCanonical
+	}
+	f, err := b.gtFromBytes(in)
+	if err != nil {
+		return nil, ErrNotInSubgroup
+	}
+	return f, nil
+}
+
+// fpFromBytes returns the field elements big-endian encoded in buf, whose length is a multiple of
+// fpByteSize, or nil if one of them is not reduced modulo p.
+func fpFromBytes(buf []byte) []*big.Int {
+	var out []*big.Int
+	for i := 0; i < len(buf); i += fpByteSize {
+		e := new(big.Int).SetBytes(buf[i : i+fpByteSize])
+		if e.Cmp(fieldModulus) >= 0 {
+			return nil
+		}
+		out = append(out, e)
+	}
+	return out
+}
@@ -0,0 +1,293 @@
+package bls
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/drand/kyber"
+	"github.com/stretchr/testify/require"
+)
+
+// The differential tests run the same operations with random inputs on every backend, and report the first
+// operation whose results differ, along with the inputs shrunk to a minimal reproducer.
+
+// differentialOp is an operation run on every backend. Its inputs are byte strings, scalars being reduced
+// modulo the group order, so that they can be shrunk by removing bytes.
+type differentialOp struct {
+	name   string
+	inputs []string
+	gen    func(r *rand.Rand) [][]byte
+	run    func(s *Suite, in [][]byte) ([]byte, error)
+}
+
+// result runs op on the backend b, and returns its output, with errors and panics reduced to their kind
+// since the messages of the backends differ.
+func (op *differentialOp) result(b Backend, in [][]byte) (out string) {
+	defer func() {
+		if r := recover(); r != nil {
+			out = "panic"
+		}
+	}()
+	buf, err := op.run(NewBLS12381Suite(WithBackend(b)).(*Suite), in)
+	if err != nil {
+		return "error"
+	}
+	return fmt.Sprintf("%x", buf)
+}
+
+// diverges returns the index of the first backend whose result differs from the one of the first backend,
+// or -1 if they all agree.
+func (op *differentialOp) diverges(bs []Backend, in [][]byte) int {
+	ref := op.result(bs[0], in)
+	for i := 1; i < len(bs); i++ {
+		if op.result(bs[i], in) != ref {
+			return i
+		}
+	}
+	return -1
+}
+
+// shrink removes bytes from the inputs as long as the backends bs diverge.
+func (op *differentialOp) shrink(bs []Backend, in [][]byte) [][]byte {
+	for i := range in {
+		for n := len(in[i]) / 2; n > 0; n /= 2 {
+			for j := 0; j+n <= len(in[i]); {
+				candidate := append([][]byte(nil), in...)
+				candidate[i] = append(append([]byte(nil), in[i][:j]...), in[i][j+n:]...)
+				if op.diverges(bs, candidate) >= 0 {
+					in = candidate
+					continue
+				}
+				j += n
+			}
+		}
+	}
+	return in
+}
+
+// report describes the divergence of the backends a and b on the inputs in.
+func (op *differentialOp) report(a, b Backend, in [][]byte) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s diverges between the %s and %s backends\n", op.name, a, b)
+	for i, name := range op.inputs {
+		fmt.Fprintf(&sb, "  %s = %x\n", name, in[i])
+	}
+	fmt.Fprintf(&sb, "  %s: %s\n", a, op.result(a, in))
+	fmt.Fprintf(&sb, "  %s: %s", b, op.result(b, in))
+	return sb.String()
+}
+
+// differential runs op count times on the backends bs, and returns the report of the first divergence, or
+// the empty string.
+func differential(op *differentialOp, bs []Backend, r *rand.Rand, count int) string {
+	for i := 0; i < count; i++ {
+		in := op.gen(r)
+		if op.diverges(bs, in) < 0 {
+			continue
+		}
+		in = op.shrink(bs, in)
+		return op.report(bs[0], bs[op.diverges(bs, in)], in)
+	}
+	return ""
+}
+
+func randomBytes(r *rand.Rand, min, max int) []byte {
+	buf := make([]byte, min+r.Intn(max-min+1))
+	r.Read(buf)
+	return buf
+}
+
+// mutate returns buf with one of its bytes randomly changed or truncated, or unchanged.
+func mutate(r *rand.Rand, buf []byte) []byte {
+	buf = append([]byte(nil), buf...)
+	switch r.Intn(4) {
+	case 0:
+		buf[r.Intn(len(buf))] ^= byte(1 + r.Intn(255))
+	case 1:
+		buf[0] ^= 0x20 << r.Intn(3)
+	case 2:
+		buf = buf[:r.Intn(len(buf))]
+	}
+	return buf
+}
+
+func marshal(ps ...kyber.Point) ([]byte, error) {
+	var out []byte
+	for _, p := range ps {
+		buf, err := p.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, buf...)
+	}
+	return out, nil
+}
+
+func hashTo(g kyber.Group, msg []byte) kyber.Point {
+	return g.Point().(kyber.HashablePoint).Hash(msg)
+}
+
+func scalar(g kyber.Group, buf []byte) kyber.Scalar {
+	return g.Scalar().SetBytes(buf)
+}
+
+// gtBase returns the pairing of the generators, GT having no Base.
+func gtBase(s *Suite) kyber.Point {
+	return s.Pair(s.G1().Point().Base(), s.G2().Point().Base())
+}
+
+// unmarshalOp decodes mutations of encodings of random points.
+func unmarshalOp(name string, group func(s *Suite) kyber.Group) *differentialOp {
+	return &differentialOp{
+		name:   name,
+		inputs: []string{"encoding"},
+		gen: func(r *rand.Rand) [][]byte {
+			s := NewBLS12381Suite().(*Suite)
+			g := group(s)
+			_, isGT := g.Point().(*KyberGT)
+			var p kyber.Point
+			switch {
+			case isGT:
+				p = g.Point().Mul(scalar(g, randomBytes(r, 0, 32)), gtBase(s))
+			case r.Intn(2) == 0:
+				p = hashTo(g, randomBytes(r, 0, 16))
+			default:
+				p = g.Point().Mul(scalar(g, randomBytes(r, 0, 32)), nil)
+			}
+			buf, _ := p.MarshalBinary()
+			return [][]byte{mutate(r, buf)}
+		},
+		run: func(s *Suite, in [][]byte) ([]byte, error) {
+			p := group(s).Point()
+			if err := p.UnmarshalBinary(in[0]); err != nil {
+				return nil, err
+			}
+			buf, err := p.MarshalBinary()
+			return append(buf, fmt.Sprint(p.(GroupChecker).IsInCorrectGroup())...), err
+		},
+	}
+}
+
+func differentialOps() []*differentialOp {
+	g1 := func(s *Suite) kyber.Group { return s.G1() }
+	g2 := func(s *Suite) kyber.Group { return s.G2() }
+	gt := func(s *Suite) kyber.Group { return s.GT() }
+	hashOp := func(name string, group func(s *Suite) kyber.Group) *differentialOp {
+		return &differentialOp{
+			name:   name,
+			inputs: []string{"msg", "dst"},
+			gen: func(r *rand.Rand) [][]byte {
+				// tags longer than 255 bytes are hashed first
+				return [][]byte{randomBytes(r, 0, 64), randomBytes(r, 1, 300)}
+			},
+			run: func(s *Suite, in [][]byte) ([]byte, error) {
+				s.SetDomainG1(in[1])
+				s.SetDomainG2(in[1])
+				return marshal(hashTo(group(s), in[0]))
+			},
+		}
+	}
+	arithmeticOp := func(name string, group func(s *Suite) kyber.Group) *differentialOp {
+		return &differentialOp{
+			name:   name,
+			inputs: []string{"scalar", "msg1", "msg2"},
+			gen: func(r *rand.Rand) [][]byte {
+				return [][]byte{randomBytes(r, 0, 32), randomBytes(r, 0, 16), randomBytes(r, 0, 16)}
+			},
+			run: func(s *Suite, in [][]byte) ([]byte, error) {
+				g := group(s)
+				k := scalar(g, in[0])
+				p, q := hashTo(g, in[1]), hashTo(g, in[2])
+				return marshal(
+					g.Point().Mul(k, nil),
+					g.Point().Mul(k, p),
+					g.Point().Add(p, q),
+					g.Point().Sub(p, q),
+					g.Point().Neg(p),
+					g.Point().Add(p, p),
+				)
+			},
+		}
+	}
+	return []*differentialOp{
+		hashOp("G1.Hash", g1),
+		hashOp("G2.Hash", g2),
+		arithmeticOp("G1 arithmetic", g1),
+		arithmeticOp("G2 arithmetic", g2),
+		{
+			name:   "Suite.Pair",
+			inputs: []string{"scalar1", "scalar2", "msg"},
+			gen: func(r *rand.Rand) [][]byte {
+				return [][]byte{randomBytes(r, 0, 32), randomBytes(r, 0, 32), randomBytes(r, 0, 16)}
+			},
+			run: func(s *Suite, in [][]byte) ([]byte, error) {
+				a, b := scalar(s.G1(), in[0]), scalar(s.G2(), in[1])
+				p := s.G1().Point().Mul(a, hashTo(s.G1(), in[2]))
+				q := s.G2().Point().Mul(b, nil)
+				ab := s.G1().Point().Mul(s.G1().Scalar().Mul(a, b), hashTo(s.G1(), in[2]))
+				out, err := marshal(s.Pair(p, q))
+				check := s.ValidatePairing(p, q, ab, s.G2().Point().Base())
+				return append(out, fmt.Sprint(check)...), err
+			},
+		},
+		{
+			name:   "GT arithmetic",
+			inputs: []string{"scalar1", "scalar2"},
+			gen: func(r *rand.Rand) [][]byte {
+				return [][]byte{randomBytes(r, 0, 32), randomBytes(r, 0, 32)}
+			},
+			run: func(s *Suite, in [][]byte) ([]byte, error) {
+				a, b := scalar(s.GT(), in[0]), scalar(s.GT(), in[1])
+				e := gtBase(s)
+				x, y := s.GT().Point().Mul(a, e), s.GT().Point().Mul(b, e)
+				return marshal(x, s.GT().Point().Add(x, y), s.GT().Point().Sub(x, y), s.GT().Point().Neg(x))
+			},
+		},
+		unmarshalOp("G1.UnmarshalBinary", g1),
+		unmarshalOp("G2.UnmarshalBinary", g2),
+		unmarshalOp("GT.UnmarshalBinary", gt),
+	}
+}
+
+func TestDifferentialBackends(t *testing.T) {
+	count := 20
+	if testing.Short() {
+		count = 2
+	}
+	seed := time.Now().UnixNano()
+	t.Logf("seed %d", seed)
+	r := rand.New(rand.NewSource(seed))
+	for _, op := range differentialOps() {
+		t.Run(op.name, func(t *testing.T) {
+			if report := differential(op, backends, r, count); report != "" {
+				t.Fatal(report)
+			}
+		})
+	}
+}
+
+// The harness reports a divergence with inputs shrunk to the bytes causing it.
+func TestDifferentialReport(t *testing.T) {
+	op := &differentialOp{
+		name:   "broken",
+		inputs: []string{"msg"},
+		gen: func(r *rand.Rand) [][]byte {
+			return [][]byte{append(randomBytes(r, 0, 32), 0x42)}
+		},
+		run: func(s *Suite, in [][]byte) ([]byte, error) {
+			if s.Backend() == BackendGnark && bytes.IndexByte(in[0], 0x42) >= 0 {
+				panic("broken")
+			}
+			return in[0], nil
+		},
+	}
+	report := differential(op, backends, rand.New(rand.NewSource(1)), 1)
+	require.Equal(t, "broken diverges between the kilic and gnark backends\n"+
+		"  msg = 42\n"+
+		"  kilic: 42\n"+
+		"  gnark: panic", report)
+}
@@ -0,0 +1,201 @@
+// Package dleq implements the non-interactive Chaum-Pedersen proofs of discrete logarithm equality on
+// BLS12-381: a proof that xG and xH share the discrete logarithm x with respect to the bases G and H, which
+// are both in G1, both in G2 or both in GT.
+//
+// The prover picks v and sends the commitments vG and vH, and the response r = v - cx to the challenge c,
+// which is derived from the statement and the commitments with hash_to_field of RFC 9380 under Domain. The
+// verifier checks that vG = rG + c xG and vH = rH + c xH.
+package dleq
+
+import (
+	"crypto/cipher"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/drand/kyber"
+	bls "github.com/drand/kyber-bls12381"
+)
+
+// Domain is the DST of the challenges.
+var Domain = []byte("DLEQ_BLS12381_XMD:SHA-256_RO_")
+
+// ProofSize is the size of the encoding of a proof: the challenge followed by the response.
+const ProofSize = 64
+
+// ErrInvalidProof is returned when a proof does not verify.
+var ErrInvalidProof = errors.New("dleq: invalid proof")
+
+var (
+	errGroup  = errors.New("dleq: the points must be in the same group of BLS12-381")
+	errLength = errors.New("dleq: inputs of different lengths")
+	errNil    = errors.New("dleq: nil statement or proof")
+)
+
+// Statement is the claim that XG = xG and XH = xH for some x.
+type Statement struct {
+	G, H, XG, XH kyber.Point
+}
+
+// Proof is a proof of discrete logarithm equality. VG and VH are the commitments of the prover, which the
+// encoding of the proof does not keep: they are recomputed to verify decoded proofs.
+type Proof struct {
+	C, R   kyber.Scalar
+	VG, VH kyber.Point
+}
+
+// NewProof returns the proof that xG and xH have the discrete logarithm x, along with xG and xH. The nonce
+// of the proof is picked from rand.
+func NewProof(g, h kyber.Point, x kyber.Scalar, rand cipher.Stream) (proof *Proof, xG, xH kyber.Point, err error) {
+	if err := checkGroup(g, h); err != nil {
+		return nil, nil, nil, err
+	}
+	xG, xH = g.Clone().Mul(x, g), h.Clone().Mul(x, h)
+	v := bls.NewKyberScalar().Pick(rand)
+	vG, vH := g.Clone().Mul(v, g), h.Clone().Mul(v, h)
+	c, err := challenge(&Statement{G: g, H: h, XG: xG, XH: xH}, vG, vH)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	r := bls.NewKyberScalar().Mul(c, x)
+	r.Sub(v, r)
+	return &Proof{C: c, R: r, VG: vG, VH: vH}, xG, xH, nil
+}
+
+// Verify checks the proof of the statement, returning ErrInvalidProof if it does not verify.
+func (p *Proof) Verify(s *Statement) error {
+	if err := s.check(); err != nil {
+		return err
+	}
+	vG, vH := p.commitments(s)
+	if p.VG != nil && p.VH != nil && (!vG.Equal(p.VG) || !vH.Equal(p.VH)) {
+		return ErrInvalidProof
+	}
+	c, err := challenge(s, vG, vH)
+	if err != nil {
+		return err
+	}
+	if !c.Equal(p.C) {
+		return ErrInvalidProof
+	}
+	return nil
+}
+
+// VerifyBatch checks the proofs of the statements, returning ErrInvalidProof if one of them does not verify.
+// The verification equations of the proofs holding their commitments, as returned by NewProof, are combined
+// with random scalars from rand into one multi-scalar multiplication per group, the commitments of the other
+// proofs being recomputed.
+func VerifyBatch(statements []*Statement, proofs []*Proof, rand cipher.Stream) error {
+	if len(statements) != len(proofs) {
+		return errLength
+	}
+	// the terms of the combinations, by group
+	points := make(map[reflect.Type][]kyber.Point)
+	scalars := make(map[reflect.Type][]kyber.Scalar)
+	for i, p := range proofs {
+		s := statements[i]
+		if s == nil || p == nil {
+			return fmt.Errorf("%w at index %d", errNil, i)
+		}
+		if err := s.check(); err != nil {
+			return err
+		}
+		if p.VG == nil || p.VH == nil {
+			if err := p.Verify(s); err != nil {
+				return err
+			}
+			continue
+		}
+		c, err := challenge(s, p.VG, p.VH)
+		if err != nil {
+			return err
+		}
+		if !c.Equal(p.C) {
+			return ErrInvalidProof
+		}
+		// ρ (rG + c xG - vG) + σ (rH + c xH - vH) = 0
+		rho, sigma := bls.NewKyberScalar().Pick(rand), bls.NewKyberScalar().Pick(rand)
+		t := reflect.TypeOf(s.G)
+		points[t] = append(points[t], s.G, s.XG, p.VG, s.H, s.XH, p.VH)
+		for _, k := range []kyber.Scalar{rho, sigma} {
+			scalars[t] = append(scalars[t], bls.NewKyberScalar().Mul(k, p.R), bls.NewKyberScalar().Mul(k, p.C),
+				bls.NewKyberScalar().Neg(k))
+		}
+	}
+	for t, ps := range points {
+		sum := bls.LinearCombination(ps[0].Clone(), ps, scalars[t])
+		if !sum.Equal(sum.Clone().Null()) {
+			return ErrInvalidProof
+		}
+	}
+	return nil
+}
+
+// MarshalBinary returns the compact encoding of the proof, the challenge followed by the response, both
+// big-endian.
+func (p *Proof) MarshalBinary() ([]byte, error) {
+	c, err := p.C.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	r, err := p.R.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return append(c, r...), nil
+}
+
+// UnmarshalBinary sets the proof from its compact encoding, without the commitments.
+func (p *Proof) UnmarshalBinary(buf []byte) error {
+	if len(buf) != ProofSize {
+		return fmt.Errorf("dleq: invalid proof length %d", len(buf))
+	}
+	c, r := bls.NewKyberScalar(), bls.NewKyberScalar()
+	if err := c.UnmarshalBinary(buf[:ProofSize/2]); err != nil {
+		return fmt.Errorf("dleq: invalid challenge: %w", err)
+	}
+	if err := r.UnmarshalBinary(buf[ProofSize/2:]); err != nil {
+		return fmt.Errorf("dleq: invalid response: %w", err)
+	}
+	*p = Proof{C: c, R: r}
+	return nil
+}
+
+// commitments returns rG + c xG and rH + c xH.
+func (p *Proof) commitments(s *Statement) (vG, vH kyber.Point) {
+	vG = bls.LinearCombination(s.G.Clone(), []kyber.Point{s.G, s.XG}, []kyber.Scalar{p.R, p.C})
+	vH = bls.LinearCombination(s.H.Clone(), []kyber.Point{s.H, s.XH}, []kyber.Scalar{p.R, p.C})
+	return vG, vH
+}
+
+// check returns an error if the points of the statement are not in the same group.
+func (s *Statement) check() error {
+	return checkGroup(s.G, s.H, s.XG, s.XH)
+}
+
+func checkGroup(ps ...kyber.Point) error {
+	for _, p := range ps {
+		switch p.(type) {
+		case *bls.KyberG1, *bls.KyberG2, *bls.KyberGT:
+		default:
+			return errGroup
+		}
+		if reflect.TypeOf(p) != reflect.TypeOf(ps[0]) {
+			return errGroup
+		}
+	}
+	return nil
+}
+
+// challenge returns the hash of the statement and the commitments, in their compressed encodings.
+func challenge(s *Statement, vG, vH kyber.Point) (kyber.Scalar, error) {
+	var msg []byte
+	for _, p := range []kyber.Point{s.G, s.H, s.XG, s.XH, vG, vH} {
+		buf, err := p.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		msg = append(msg, buf...)
+	}
+	return bls.HashToScalar(msg, Domain), nil
+}
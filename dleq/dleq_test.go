@@ -0,0 +1,125 @@
+package dleq
+
+import (
+	"testing"
+
+	"github.com/drand/kyber"
+	bls "github.com/drand/kyber-bls12381"
+	"github.com/drand/kyber/pairing"
+	"github.com/drand/kyber/util/random"
+	"github.com/stretchr/testify/require"
+)
+
+var backends = []bls.Backend{bls.BackendKilic, bls.BackendGnark}
+
+// bases returns two random bases of G1, G2 and GT.
+func bases(suite pairing.Suite) map[string][2]kyber.Point {
+	pick := func(g kyber.Group) kyber.Point { return g.Point().Pick(random.New()) }
+	return map[string][2]kyber.Point{
+		"G1": {suite.G1().Point().Base(), pick(suite.G1())},
+		"G2": {pick(suite.G2()), pick(suite.G2())},
+		"GT": {suite.Pair(pick(suite.G1()), suite.G2().Point().Base()), suite.Pair(pick(suite.G1()), pick(suite.G2()))},
+	}
+}
+
+func TestProof(t *testing.T) {
+	for _, b := range backends {
+		suite := bls.NewBLS12381Suite(bls.WithBackend(b))
+		for name, gh := range bases(suite) {
+			t.Run(b.String()+"/"+name, func(t *testing.T) {
+				g, h := gh[0], gh[1]
+				x := bls.NewKyberScalar().Pick(random.New())
+				proof, xG, xH, err := NewProof(g, h, x, random.New())
+				require.NoError(t, err)
+				s := &Statement{G: g, H: h, XG: xG, XH: xH}
+				require.NoError(t, proof.Verify(s))
+
+				// xG and yH
+				y := bls.NewKyberScalar().Add(x, bls.NewKyberScalar().One())
+				wrong := &Statement{G: g, H: h, XG: xG, XH: h.Clone().Mul(y, h)}
+				require.ErrorIs(t, proof.Verify(wrong), ErrInvalidProof)
+				other, _, _, err := NewProof(g, h, y, random.New())
+				require.NoError(t, err)
+				require.ErrorIs(t, other.Verify(s), ErrInvalidProof)
+				require.ErrorIs(t, proof.Verify(&Statement{G: h, H: g, XG: xH, XH: xG}), ErrInvalidProof)
+
+				// the compact encoding
+				buf, err := proof.MarshalBinary()
+				require.NoError(t, err)
+				require.Len(t, buf, ProofSize)
+				decoded := new(Proof)
+				require.NoError(t, decoded.UnmarshalBinary(buf))
+				require.Nil(t, decoded.VG)
+				require.NoError(t, decoded.Verify(s))
+				require.ErrorIs(t, decoded.Verify(wrong), ErrInvalidProof)
+
+				// commitments which do not match the response
+				tampered := *proof
+				tampered.VG = g
+				require.ErrorIs(t, tampered.Verify(s), ErrInvalidProof)
+			})
+		}
+	}
+}
+
+func TestVerifyBatch(t *testing.T) {
+	suite := bls.NewBLS12381Suite()
+	var statements []*Statement
+	var proofs []*Proof
+	for i := 0; i < 2; i++ {
+		for _, gh := range bases(suite) {
+			proof, xG, xH, err := NewProof(gh[0], gh[1], bls.NewKyberScalar().Pick(random.New()), random.New())
+			require.NoError(t, err)
+			statements = append(statements, &Statement{G: gh[0], H: gh[1], XG: xG, XH: xH})
+			proofs = append(proofs, proof)
+		}
+	}
+	require.NoError(t, VerifyBatch(statements, proofs, random.New()))
+	require.NoError(t, VerifyBatch(nil, nil, random.New()))
+	// nil entries are rejected, as lengths that differ
+	withNil := append(append([]*Proof(nil), proofs[:1]...), nil)
+	require.ErrorIs(t, VerifyBatch(statements[:2], withNil, random.New()), errNil)
+	require.ErrorIs(t, VerifyBatch([]*Statement{statements[0], nil}, proofs[:2], random.New()), errNil)
+
+	// decoded proofs are verified on their own
+	buf, err := proofs[1].MarshalBinary()
+	require.NoError(t, err)
+	decoded := new(Proof)
+	require.NoError(t, decoded.UnmarshalBinary(buf))
+	mixed := append([]*Proof{decoded}, proofs[2:]...)
+	require.NoError(t, VerifyBatch(statements[1:], mixed, random.New()))
+	require.ErrorIs(t, VerifyBatch(statements[:len(mixed)], mixed, random.New()), ErrInvalidProof)
+
+	// the statements of the first two proofs swapped
+	statements[0], statements[1] = statements[1], statements[0]
+	require.ErrorIs(t, VerifyBatch(statements, proofs, random.New()), ErrInvalidProof)
+	require.Error(t, VerifyBatch(statements[1:], proofs, random.New()))
+
+	// a response which does not match the commitments, with the right challenge
+	statements[0], statements[1] = statements[1], statements[0]
+	tampered := *proofs[3]
+	tampered.R = bls.NewKyberScalar().Add(tampered.R, bls.NewKyberScalar().One())
+	proofs[3] = &tampered
+	require.ErrorIs(t, VerifyBatch(statements, proofs, random.New()), ErrInvalidProof)
+}
+
+func TestErrors(t *testing.T) {
+	suite := bls.NewBLS12381Suite()
+	g1, g2 := suite.G1().Point().Base(), suite.G2().Point().Base()
+	_, _, _, err := NewProof(g1, g2, bls.NewKyberScalar().One(), random.New())
+	require.Error(t, err)
+	proof, xG, xH, err := NewProof(g1, g1, bls.NewKyberScalar().One(), random.New())
+	require.NoError(t, err)
+	err = proof.Verify(&Statement{G: g1, H: g1, XG: xG, XH: g2})
+	require.Error(t, err)
+	require.NotErrorIs(t, err, ErrInvalidProof)
+	require.NoError(t, proof.Verify(&Statement{G: g1, H: g1, XG: xG, XH: xH}))
+
+	var p Proof
+	require.Error(t, p.UnmarshalBinary(make([]byte, ProofSize-1)))
+	buf := make([]byte, ProofSize)
+	for i := range buf[:ProofSize/2] {
+		buf[i] = 0xff
+	}
+	require.Error(t, p.UnmarshalBinary(buf), "non canonical challenge")
+}
@@ -0,0 +1,62 @@
+package bls
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// The domain encoding of a G1 or G2 point, written by MarshalWithDomain, is
+//
+//	I2OSP(len(DST), 2) || DST || I2OSP(len(ID), 1) || ID || point
+//
+// where DST is the tag used by Hash, the default one included, ID is the String of the expander, e.g.
+// "XMD:SHA-256", and point is the compressed point written by MarshalBinary.
+
+var (
+	errDomainEncoding  = errors.New("bls12-381: invalid domain encoding")
+	errDomainTooLong   = errors.New("bls12-381: domain too long to be encoded")
+	errUnknownExpander = errors.New("bls12-381: unknown expander in domain encoding")
+)
+
+// knownExpanders are the expanders UnmarshalWithDomain can restore.
+var knownExpanders = []Expander{ExpanderXMDSHA256, ExpanderXMDSHA512, ExpanderXOFSHAKE128, ExpanderXOFSHAKE256}
+
+// marshalDomain returns the domain encoding of the compressed point with the DST dst and the expander exp.
+func marshalDomain(dst []byte, exp Expander, point []byte) ([]byte, error) {
+	id := expanderOrDefault(exp).String()
+	if len(dst) > 0xffff || len(id) > 0xff {
+		return nil, errDomainTooLong
+	}
+	out := make([]byte, 0, 2+len(dst)+1+len(id)+len(point))
+	out = binary.BigEndian.AppendUint16(out, uint16(len(dst)))
+	out = append(out, dst...)
+	out = append(out, byte(len(id)))
+	out = append(out, id...)
+	return append(out, point...), nil
+}
+
+// unmarshalDomain parses a domain encoding, returning its DST, its expander and the encoding of its point.
+// The expander is one of knownExpanders, or current if it has the encoded ID.
+func unmarshalDomain(buf []byte, current Expander) ([]byte, Expander, []byte, error) {
+	if len(buf) < 2 {
+		return nil, nil, nil, errDomainEncoding
+	}
+	n := int(binary.BigEndian.Uint16(buf))
+	buf = buf[2:]
+	if len(buf) < n+1 {
+		return nil, nil, nil, errDomainEncoding
+	}
+	dst := append([]byte(nil), buf[:n]...)
+	m := int(buf[n])
+	buf = buf[n+1:]
+	if len(buf) < m {
+		return nil, nil, nil, errDomainEncoding
+	}
+	id := string(buf[:m])
+	for _, exp := range append([]Expander{current}, knownExpanders...) {
+		if exp != nil && exp.String() == id {
+			return dst, exp, buf[m:], nil
+		}
+	}
+	return nil, nil, nil, errUnknownExpander
+}
@@ -0,0 +1,95 @@
+package bls
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/drand/kyber"
+	"github.com/drand/kyber/util/random"
+	"github.com/stretchr/testify/require"
+)
+
+type domainPoint interface {
+	kyber.Point
+	MarshalWithDomain() ([]byte, error)
+	UnmarshalWithDomain(buf []byte) error
+}
+
+func TestMarshalWithDomain(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		point func() domainPoint
+	}{
+		{"G1 default", func() domainPoint { return NullKyberG1() }},
+		{"G1 custom DST", func() domainPoint { return NullKyberG1([]byte("custom DST")...) }},
+		{"G1 SHAKE256", func() domainPoint { return NullKyberG1WithExpander(ExpanderXOFSHAKE256) }},
+		{"G2 default", func() domainPoint { return NullKyberG2() }},
+		{"G2 custom DST", func() domainPoint { return NullKyberG2([]byte("custom DST")...) }},
+		{"G2 SHA-512", func() domainPoint { return NullKyberG2WithExpander(ExpanderXMDSHA512, []byte("custom DST")...) }},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := tc.point().Pick(random.New()).(domainPoint)
+			buf, err := p.MarshalWithDomain()
+			require.NoError(t, err)
+
+			// the domain is restored into a point of any domain
+			var q domainPoint = NullKyberG1([]byte("another DST")...)
+			if _, ok := p.(*KyberG2); ok {
+				q = NullKyberG2([]byte("another DST")...)
+			}
+			require.NoError(t, q.UnmarshalWithDomain(buf))
+			require.True(t, q.Equal(p))
+
+			// the compressed point ends the encoding
+			compressed, err := p.MarshalBinary()
+			require.NoError(t, err)
+			require.Equal(t, compressed, buf[len(buf)-len(compressed):])
+
+			for _, n := range []int{0, 1, 2, 5, len(buf) - len(compressed) - 1, len(buf) - 1} {
+				require.Error(t, tc.point().UnmarshalWithDomain(buf[:n]), "truncated to %d bytes", n)
+			}
+		})
+	}
+}
+
+func TestMarshalWithDomainFormat(t *testing.T) {
+	p := NullKyberG1([]byte("DST")...).Base().(*KyberG1)
+	buf, err := p.MarshalWithDomain()
+	require.NoError(t, err)
+	g, err := p.MarshalBinary()
+	require.NoError(t, err)
+	require.Equal(t, "0003"+hex.EncodeToString([]byte("DST"))+"0b"+hex.EncodeToString([]byte("XMD:SHA-256"))+
+		hex.EncodeToString(g), hex.EncodeToString(buf))
+
+	// the default DST is written out, and restored as the default
+	buf, err = NullKyberG2().MarshalWithDomain()
+	require.NoError(t, err)
+	require.Equal(t, DefaultDomainG2(), buf[2:2+len(DefaultDomainG2())])
+	q := NullKyberG2([]byte("DST")...)
+	require.NoError(t, q.UnmarshalWithDomain(buf))
+	require.Nil(t, q.dst)
+	require.Nil(t, q.exp)
+}
+
+func TestUnmarshalWithDomainExpander(t *testing.T) {
+	exp := &testExpander{ExpanderXMDSHA256}
+	p := NullKyberG1WithExpander(exp).Base().(*KyberG1)
+	buf, err := p.MarshalWithDomain()
+	require.NoError(t, err)
+	// the expander of the receiver is used if it has the encoded ID
+	q := NullKyberG1WithExpander(exp)
+	require.NoError(t, q.UnmarshalWithDomain(buf))
+	require.True(t, q.Equal(p))
+
+	buf[2+len(DomainG1WithExpander(exp))+1] ^= 1
+	require.ErrorIs(t, NullKyberG1().UnmarshalWithDomain(buf), errUnknownExpander)
+}
+
+// testExpander is a custom expander, with another ID.
+type testExpander struct {
+	Expander
+}
+
+func (e *testExpander) String() string {
+	return "XMD:TEST"
+}
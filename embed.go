@@ -0,0 +1,86 @@
+package bls
+
+import (
+	"crypto/cipher"
+	"errors"
+	"math/big"
+)
+
+// Data is embedded in the x-coordinate of a point of the curve with try-and-increment. Each of its n
+// coordinates in Fp, in the order of the ZCash format, starts with a zero byte so that it is canonical, and
+// the other 47 bytes of the coordinates hold in turn a random byte, the length of the data, the data and
+// random padding. A point whose x-coordinate holds the data is in G1 or G2 with negligible probability, and
+// clearing the cofactor would lose its x-coordinate, so the points are left outside of the subgroup.
+
+var errEmbedding = errors.New("bls12-381: point does not hold embedded data")
+
+// embedLen returns the number of bytes embedded in an x-coordinate of n elements of Fp.
+func embedLen(n int) int {
+	return n*(fpByteSize-1) - 2
+}
+
+// embedCoordinate returns a random x-coordinate of n elements of Fp holding the first embedLen(n) bytes of
+// data, and a random bit for the sign of y.
+func embedCoordinate(data []byte, n int, rand cipher.Stream) ([]byte, bool) {
+	dl := min(len(data), embedLen(n))
+	body := make([]byte, n*(fpByteSize-1)+1)
+	rand.XORKeyStream(body, body)
+	body[1] = byte(dl)
+	copy(body[2:], data[:dl])
+
+	x := make([]byte, n*fpByteSize)
+	for i := range n {
+		copy(x[i*fpByteSize+1:(i+1)*fpByteSize], body[i*(fpByteSize-1):])
+	}
+	return x, body[len(body)-1]&1 == 1
+}
+
+// embeddedData returns the data held by the x-coordinate x of n elements of Fp.
+func embeddedData(x []byte, n int) ([]byte, error) {
+	body := make([]byte, 0, n*(fpByteSize-1))
+	for i := range n {
+		if x[i*fpByteSize] != 0 {
+			return nil, errEmbedding
+		}
+		body = append(body, x[i*fpByteSize+1:(i+1)*fpByteSize]...)
+	}
+	dl := int(body[1])
+	if dl > embedLen(n) {
+		return nil, errEmbedding
+	}
+	return append([]byte(nil), body[2:2+dl]...), nil
+}
+
+// embedG1 returns the uncompressed encoding of a point of E1 holding data.
+func embedG1(data []byte, rand cipher.Stream) []byte {
+	for {
+		x, neg := embedCoordinate(data, 1, rand)
+		xx := new(big.Int).SetBytes(x)
+		// y^2 = x^3 + 4
+		y := fpSqrt(fpAdd(fpMul(fpMul(xx, xx), xx), big.NewInt(4)))
+		if y == nil {
+			continue
+		}
+		if neg {
+			y = fpNeg(y)
+		}
+		return append(x, fpToBytes(y)...)
+	}
+}
+
+// embedG2 returns the uncompressed encoding of a point of E2 holding data.
+func embedG2(data []byte, rand cipher.Stream) []byte {
+	for {
+		x, neg := embedCoordinate(data, 2, rand)
+		xx := newFp2(new(big.Int).SetBytes(x[fpByteSize:]), new(big.Int).SetBytes(x[:fpByteSize]))
+		// y^2 = x^3 + 4 (1 + u)
+		y := fp2Sqrt(fp2Add(fp2Mul(fp2Square(xx), xx), newFp2(big.NewInt(4), big.NewInt(4))))
+		if y == nil {
+			continue
+		}
+		if neg {
+			y = fp2Neg(y)
+		}
+		return append(x, y.Bytes()...)
+	}
+}
@@ -0,0 +1,136 @@
+package bls
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+)
+
+// The kyber.Encoding of Suite is the fixed-length binary encoding of the kyber suites, implemented by
+// go.dedis.ch/fixbuf:
+//   - points and scalars are written with MarshalTo and read with UnmarshalFrom,
+//   - structs are encoded field by field, and arrays and slices element by element, without their length,
+//     so slices must have their final length before Read,
+//   - int is encoded as a big endian int32, bool as a byte, and other fixed-size values in big endian.
+//
+// Unlike fixbuf, Read allocates the nil pointers it meets, using Suite.New for points and scalars, and
+// returns errors instead of panicking.
+
+// marshalling is implemented by kyber points and scalars.
+type marshalling interface {
+	MarshalTo(w io.Writer) (int, error)
+	UnmarshalFrom(r io.Reader) (int, error)
+}
+
+func (s *Suite) read(r io.Reader, v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Interface, reflect.Ptr:
+		if v.IsNil() {
+			o := s.New(v.Type())
+			if o == nil && v.Kind() == reflect.Ptr {
+				o = reflect.New(v.Type().Elem()).Interface()
+			}
+			if o == nil || !v.CanSet() {
+				return fmt.Errorf("bls12-381: cannot read into a nil %s", v.Type())
+			}
+			v.Set(reflect.ValueOf(o))
+		}
+	case reflect.Invalid:
+		return fmt.Errorf("bls12-381: cannot read into nil")
+	}
+	if !v.CanInterface() {
+		return fmt.Errorf("bls12-381: cannot read into the unexported %s", v.Type())
+	}
+	if m, ok := v.Interface().(marshalling); ok {
+		_, err := m.UnmarshalFrom(r)
+		return err
+	}
+	switch v.Kind() {
+	case reflect.Interface, reflect.Ptr:
+		return s.read(r, v.Elem())
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if err := s.read(r, v.Field(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := s.read(r, v.Index(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if !v.CanSet() {
+		return fmt.Errorf("bls12-381: cannot read into a %s which is not a pointer", v.Type())
+	}
+	switch v.Kind() {
+	case reflect.Int:
+		var i int32
+		if err := binary.Read(r, binary.BigEndian, &i); err != nil {
+			return err
+		}
+		v.SetInt(int64(i))
+		return nil
+	case reflect.Bool:
+		var b uint8
+		if err := binary.Read(r, binary.BigEndian, &b); err != nil {
+			return err
+		}
+		v.SetBool(b != 0)
+		return nil
+	}
+	return binary.Read(r, binary.BigEndian, v.Addr().Interface())
+}
+
+func write(w io.Writer, v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Interface, reflect.Ptr:
+		if v.IsNil() {
+			return fmt.Errorf("bls12-381: cannot write a nil %s", v.Type())
+		}
+	case reflect.Invalid:
+		return fmt.Errorf("bls12-381: cannot write nil")
+	}
+	if !v.CanInterface() {
+		return fmt.Errorf("bls12-381: cannot write the unexported %s", v.Type())
+	}
+	if m, ok := v.Interface().(marshalling); ok {
+		_, err := m.MarshalTo(w)
+		return err
+	}
+	switch v.Kind() {
+	case reflect.Interface, reflect.Ptr:
+		return write(w, v.Elem())
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if err := write(w, v.Field(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := write(w, v.Index(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Int:
+		if v.Int() < math.MinInt32 || v.Int() > math.MaxInt32 {
+			return fmt.Errorf("bls12-381: cannot write the int %d on 32 bits", v.Int())
+		}
+		return binary.Write(w, binary.BigEndian, int32(v.Int()))
+	case reflect.Bool:
+		var b uint8
+		if v.Bool() {
+			b = 1
+		}
+		return binary.Write(w, binary.BigEndian, b)
+	}
+	return binary.Write(w, binary.BigEndian, v.Interface())
+}
@@ -0,0 +1,116 @@
+package bls
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strconv"
+	"testing"
+
+	"github.com/drand/kyber"
+	"github.com/drand/kyber/share"
+	"github.com/drand/kyber/util/random"
+	"github.com/stretchr/testify/require"
+)
+
+type encodingTest struct {
+	Index  int
+	Secret kyber.Scalar
+	G1     *KyberG1
+	G2     *KyberG2
+	GT     *KyberGT
+	Points []kyber.Point
+	Valid  bool
+	Nested struct {
+		Round  uint64
+		Public kyber.Point
+	}
+	Shares [2]share.PriShare
+}
+
+func TestSuiteEncoding(t *testing.T) {
+	suite := NewBLS12381Suite().(*Suite)
+	rand := random.New()
+	var in encodingTest
+	in.Index = 7
+	in.Secret = suite.G1().Scalar().Pick(rand)
+	in.G1 = suite.G1().Point().Pick(rand).(*KyberG1)
+	in.G2 = suite.G2().Point().Pick(rand).(*KyberG2)
+	in.GT = suite.Pair(in.G1, in.G2).(*KyberGT)
+	in.Points = []kyber.Point{suite.G1().Point().Pick(rand), suite.G2().Point().Pick(rand)}
+	in.Valid = true
+	in.Nested.Round = 42
+	in.Nested.Public = suite.G2().Point().Pick(rand)
+	in.Shares[0] = share.PriShare{I: 1, V: suite.G1().Scalar().Pick(rand)}
+	in.Shares[1] = share.PriShare{I: 2, V: suite.G1().Scalar().Pick(rand)}
+
+	var buf bytes.Buffer
+	require.NoError(t, suite.Write(&buf, &in, in.Secret))
+	require.Equal(t, 4+32+48+96+576+48+96+1+8+96+2*(4+32)+32, buf.Len())
+	enc := buf.Bytes()
+	// int is written on 32 bits and points and scalars with MarshalTo, as by go.dedis.ch/fixbuf
+	require.Equal(t, []byte{0, 0, 0, 7}, enc[:4])
+	secret, err := in.Secret.MarshalBinary()
+	require.NoError(t, err)
+	require.Equal(t, secret, enc[4:36])
+	require.Equal(t, secret, enc[len(enc)-32:])
+
+	// nil scalars and points of known types are allocated, kyber.Point values and slices must be initialized
+	var out encodingTest
+	out.Points = []kyber.Point{suite.G1().Point(), suite.G2().Point()}
+	out.Nested.Public = suite.G2().Point()
+	var s kyber.Scalar
+	require.NoError(t, suite.Read(bytes.NewReader(enc), &out, &s))
+	require.True(t, out.Secret.Equal(in.Secret))
+	require.True(t, out.G1.Equal(in.G1))
+	require.True(t, out.G2.Equal(in.G2))
+	require.True(t, out.GT.Equal(in.GT))
+	require.True(t, out.Points[0].Equal(in.Points[0]))
+	require.True(t, out.Points[1].Equal(in.Points[1]))
+	require.Equal(t, in.Index, out.Index)
+	require.Equal(t, in.Valid, out.Valid)
+	require.Equal(t, in.Nested.Round, out.Nested.Round)
+	require.True(t, out.Nested.Public.Equal(in.Nested.Public))
+	for i := range in.Shares {
+		require.Equal(t, in.Shares[i].I, out.Shares[i].I)
+		require.True(t, in.Shares[i].V.Equal(out.Shares[i].V))
+	}
+	require.True(t, s.Equal(in.Secret))
+
+	// the decoded points are checked
+	bad := append([]byte(nil), enc...)
+	bad[36] ^= 0x80
+	out.G1 = nil
+	require.Error(t, suite.Read(bytes.NewReader(bad), &out))
+	require.Error(t, suite.Read(bytes.NewReader(enc[:100]), &out))
+}
+
+func TestSuiteEncodingErrors(t *testing.T) {
+	suite := NewBLS12381Suite().(*Suite)
+	var pub share.PubShare
+	require.Error(t, suite.Write(new(bytes.Buffer), &pub), "nil point")
+	require.Error(t, suite.Read(bytes.NewReader(make([]byte, 100)), &pub), "nil kyber.Point")
+	require.Error(t, suite.Write(new(bytes.Buffer), "string"))
+	// ints are written on 32 bits, which they always fit in on 32-bit platforms
+	var ints bytes.Buffer
+	require.NoError(t, suite.Write(&ints, int(1)<<20))
+	require.Equal(t, []byte{0, 0x10, 0, 0}, ints.Bytes())
+	if strconv.IntSize == 64 {
+		large := int64(1) << 40
+		require.Error(t, suite.Write(new(bytes.Buffer), int(large)))
+	}
+	require.Error(t, suite.Read(bytes.NewReader(make([]byte, 4)), 1), "not a pointer")
+
+	pub = share.PubShare{I: 3, V: suite.G2().Point().Base()}
+	var buf bytes.Buffer
+	require.NoError(t, suite.Write(&buf, pub))
+	require.Equal(t, uint32(3), binary.BigEndian.Uint32(buf.Bytes()))
+	out := share.PubShare{V: suite.G2().Point()}
+	require.NoError(t, suite.Read(&buf, &out))
+	require.Equal(t, pub.I, out.I)
+	require.True(t, pub.V.Equal(out.V))
+
+	require.NotNil(t, suite.New(tScalar))
+	require.IsType(t, &KyberG1{}, suite.New(tPointG1))
+	require.IsType(t, &KyberG2{}, suite.New(tPointG2))
+	require.IsType(t, &KyberGT{}, suite.New(tPointGT))
+}
@@ -0,0 +1,129 @@
+package bls
+
+import (
+	"crypto/sha256"
+	"crypto/sha3"
+	"crypto/sha512"
+	"errors"
+	"hash"
+)
+
+// Expander implements the expand_message step of hash to field, as defined in
+// RFC 9380 section 5.3.
+type Expander interface {
+	// ExpandMessage returns outLen uniformly random bytes derived from msg and dst.
+	ExpandMessage(msg, dst []byte, outLen int) ([]byte, error)
+	// String returns the expander identifier used in RFC 9380 suite IDs, e.g. "XMD:SHA-256".
+	String() string
+}
+
+var (
+	// ExpanderXMDSHA256 is expand_message_xmd with SHA-256, the expander used by default.
+	ExpanderXMDSHA256 Expander = &expanderXMD{name: "SHA-256", newHash: sha256.New}
+	// ExpanderXMDSHA512 is expand_message_xmd with SHA-512.
+	ExpanderXMDSHA512 Expander = &expanderXMD{name: "SHA-512", newHash: sha512.New}
+	// ExpanderXOFSHAKE128 is expand_message_xof with SHAKE128, targeting k = 128 bits of security.
+	ExpanderXOFSHAKE128 Expander = &expanderXOF{name: "SHAKE128", k: 128, newXOF: sha3.NewSHAKE128}
+	// ExpanderXOFSHAKE256 is expand_message_xof with SHAKE256, targeting k = 256 bits of security.
+	ExpanderXOFSHAKE256 Expander = &expanderXOF{name: "SHAKE256", k: 256, newXOF: sha3.NewSHAKE256}
+)
+
+// expanderXMDTruncated is expand_message_xmd with SHA-256 as implemented by kilic, which does not hash tags
+// longer than 255 bytes and only keeps the low byte of their length in DST_prime. Hash uses it for such tags
+// with the default expander, unless the point has oversize domains, see WithOversizeDomains.
+var expanderXMDTruncated Expander = &expanderXMD{name: "SHA-256", newHash: sha256.New, truncateDST: true}
+
+// oversizeDSTPrefix is prepended to tags longer than 255 bytes before hashing them, see RFC 9380 section 5.3.3.
+var oversizeDSTPrefix = []byte("H2C-OVERSIZE-DST-")
+
+var errExpandLength = errors.New("bls12-381: requested expand_message output is too long")
+
+type expanderXMD struct {
+	name    string
+	newHash func() hash.Hash
+	// truncateDST uses tags longer than 255 bytes as is, see expanderXMDTruncated
+	truncateDST bool
+}
+
+func (e *expanderXMD) String() string {
+	return "XMD:" + e.name
+}
+
+func (e *expanderXMD) ExpandMessage(msg, dst []byte, outLen int) ([]byte, error) {
+	h := e.newHash()
+	ell := (outLen + h.Size() - 1) / h.Size()
+	if ell > 255 || outLen > 65535 {
+		return nil, errExpandLength
+	}
+	if len(dst) > 255 && !e.truncateDST {
+		_, _ = h.Write(oversizeDSTPrefix)
+		_, _ = h.Write(dst)
+		dst = h.Sum(nil)
+		h.Reset()
+	}
+	// DST_prime = DST || I2OSP(len(DST), 1)
+	dstPrime := append(append([]byte{}, dst...), byte(len(dst)))
+
+	// b_0 = H(Z_pad || msg || l_i_b_str || I2OSP(0, 1) || DST_prime)
+	_, _ = h.Write(make([]byte, h.BlockSize()))
+	_, _ = h.Write(msg)
+	_, _ = h.Write([]byte{byte(outLen >> 8), byte(outLen), 0})
+	_, _ = h.Write(dstPrime)
+	b0 := h.Sum(nil)
+
+	// b_1 = H(b_0 || I2OSP(1, 1) || DST_prime)
+	h.Reset()
+	_, _ = h.Write(b0)
+	_, _ = h.Write([]byte{1})
+	_, _ = h.Write(dstPrime)
+	bi := h.Sum(nil)
+
+	out := make([]byte, 0, ell*h.Size())
+	out = append(out, bi...)
+	tmp := make([]byte, h.Size())
+	for i := 2; i <= ell; i++ {
+		// b_i = H(strxor(b_0, b_(i - 1)) || I2OSP(i, 1) || DST_prime)
+		for j := range tmp {
+			tmp[j] = b0[j] ^ bi[j]
+		}
+		h.Reset()
+		_, _ = h.Write(tmp)
+		_, _ = h.Write([]byte{byte(i)})
+		_, _ = h.Write(dstPrime)
+		bi = h.Sum(nil)
+		out = append(out, bi...)
+	}
+	return out[:outLen], nil
+}
+
+type expanderXOF struct {
+	name   string
+	k      int
+	newXOF func() *sha3.SHAKE
+}
+
+func (e *expanderXOF) String() string {
+	return "XOF:" + e.name
+}
+
+func (e *expanderXOF) ExpandMessage(msg, dst []byte, outLen int) ([]byte, error) {
+	if outLen > 65535 {
+		return nil, errExpandLength
+	}
+	h := e.newXOF()
+	if len(dst) > 255 {
+		_, _ = h.Write(oversizeDSTPrefix)
+		_, _ = h.Write(dst)
+		dst = make([]byte, (2*e.k+7)/8)
+		_, _ = h.Read(dst)
+		h.Reset()
+	}
+	// msg_prime = msg || I2OSP(len_in_bytes, 2) || DST || I2OSP(len(DST), 1)
+	_, _ = h.Write(msg)
+	_, _ = h.Write([]byte{byte(outLen >> 8), byte(outLen)})
+	_, _ = h.Write(dst)
+	_, _ = h.Write([]byte{byte(len(dst))})
+	out := make([]byte, outLen)
+	_, _ = h.Read(out)
+	return out, nil
+}
@@ -0,0 +1,116 @@
+package bls
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/drand/kyber"
+	"github.com/drand/kyber/sign/bls"
+	"github.com/drand/kyber/sign/test"
+	"github.com/stretchr/testify/require"
+)
+
+// expand_message test vectors from RFC 9380 appendix K
+func TestExpanderVectors(t *testing.T) {
+	longDST := "QUUX-V01-CS02-with-expander-SHAKE128-long-DST-" + strings.Repeat("1", 210)
+	tests := []struct {
+		exp     Expander
+		dst     string
+		msg     string
+		uniform string
+	}{
+		{ExpanderXMDSHA256, "QUUX-V01-CS02-with-expander-SHA256-128", "", "68a985b87eb6b46952128911f2a4412bbc302a9d759667f87f7a21d803f07235"},
+		{ExpanderXMDSHA256, "QUUX-V01-CS02-with-expander-SHA256-128", "abc", "abba86a6129e366fc877aab32fc4ffc70120d8996c88aee2fe4b32d6c7b6437a647e6c3163d40b76a73cf6a5674ef1d890f95b664ee0afa5359a5c4e07985635bbecbac65d747d3d2da7ec2b8221b17b0ca9dc8a1ac1c07ea6a1e60583e2cb00058e77b7b72a298425cd1b941ad4ec65e8afc50303a22c0f99b0509b4c895f40"},
+		{ExpanderXMDSHA512, "QUUX-V01-CS02-with-expander-SHA512-256", "", "6b9a7312411d92f921c6f68ca0b6380730a1a4d982c507211a90964c394179ba"},
+		{ExpanderXMDSHA512, "QUUX-V01-CS02-with-expander-SHA512-256", "abc", "7f1dddd13c08b543f2e2037b14cefb255b44c83cc397c1786d975653e36a6b11bdd7732d8b38adb4a0edc26a0cef4bb45217135456e58fbca1703cd6032cb1347ee720b87972d63fbf232587043ed2901bce7f22610c0419751c065922b488431851041310ad659e4b23520e1772ab29dcdeb2002222a363f0c2b1c972b3efe1"},
+		{ExpanderXOFSHAKE128, "QUUX-V01-CS02-with-expander-SHAKE128", "", "86518c9cd86581486e9485aa74ab35ba150d1c75c88e26b7043e44e2acd735a2"},
+		{ExpanderXOFSHAKE128, "QUUX-V01-CS02-with-expander-SHAKE128", "abc", "c952f0c8e529ca8824acc6a4cab0e782fc3648c563ddb00da7399f2ae35654f4860ec671db2356ba7baa55a34a9d7f79197b60ddae6e64768a37d699a78323496db3878c8d64d909d0f8a7de4927dcab0d3dbbc26cb20a49eceb0530b431cdf47bc8c0fa3e0d88f53b318b6739fbed7d7634974f1b5c386d6230c76260d5337a"},
+		{ExpanderXOFSHAKE128, longDST, "abc", "690c8d82c7213b4282c6cb41c00e31ea1d3e2005f93ad19bbf6da40f15790c5c"},
+		{ExpanderXOFSHAKE256, "QUUX-V01-CS02-with-expander-SHAKE256", "", "2ffc05c48ed32b95d72e807f6eab9f7530dd1c2f013914c8fed38c5ccc15ad76"},
+		{ExpanderXOFSHAKE256, "QUUX-V01-CS02-with-expander-SHAKE256", "abc", "a54303e6b172909783353ab05ef08dd435a558c3197db0c132134649708e0b9b4e34fb99b92a9e9e28fc1f1d8860d85897a8e021e6382f3eea10577f968ff6df6c45fe624ce65ca25932f679a42a404bc3681efe03fcd45ef73bb3a8f79ba784f80f55ea8a3c367408f30381299617f50c8cf8fbb21d0f1e1d70b0131a7b6fbe"},
+	}
+	for _, tt := range tests {
+		exp, _ := hex.DecodeString(tt.uniform)
+		out, err := tt.exp.ExpandMessage([]byte(tt.msg), []byte(tt.dst), len(exp))
+		require.NoError(t, err)
+		require.Equal(t, tt.uniform, hex.EncodeToString(out), "%s %q", tt.exp, tt.msg)
+	}
+}
+
+func TestExpanderMatchesDefaultHash(t *testing.T) {
+	for _, msg := range []string{"", "abc", "1234"} {
+		g1 := NullKyberG1().Hash([]byte(msg))
+		p1, err := hashToCurveG1(defaultBackend, []byte(msg), domainG1, ExpanderXMDSHA256)
+		require.NoError(t, err)
+		require.True(t, g1.Equal(newKyberG1(p1, nil, nil)))
+
+		g2 := NullKyberG2().Hash([]byte(msg))
+		p2, err := hashToCurveG2(defaultBackend, []byte(msg), domainG2, ExpanderXMDSHA256)
+		require.NoError(t, err)
+		require.True(t, g2.Equal(newKyberG2(p2, nil, nil)))
+	}
+}
+
+func TestExpanderSuiteID(t *testing.T) {
+	require.Equal(t, "BLS12381G1_XMD:SHA-256_SSWU_RO_", SuiteIDG1(nil))
+	require.Equal(t, "BLS12381G2_XOF:SHAKE256_SSWU_RO_", SuiteIDG2(ExpanderXOFSHAKE256))
+	require.Equal(t, DefaultDomainG1(), DomainG1WithExpander(ExpanderXMDSHA256))
+	require.Equal(t, "BLS_SIG_BLS12381G2_XMD:SHA-512_SSWU_RO_NUL_", string(DomainG2WithExpander(ExpanderXMDSHA512)))
+
+	// the default expander is represented internally as nil
+	require.Nil(t, NullKyberG1WithExpander(ExpanderXMDSHA256).exp)
+	p := NullKyberG2WithExpander(ExpanderXOFSHAKE128, DomainG2WithExpander(ExpanderXOFSHAKE128)...)
+	require.Nil(t, p.dst)
+	require.True(t, p.Clone().Equal(p))
+	require.False(t, p.Equal(NullKyberG2()))
+}
+
+func TestSuiteWithExpander(t *testing.T) {
+	for _, exp := range []Expander{ExpanderXMDSHA512, ExpanderXOFSHAKE128, ExpanderXOFSHAKE256} {
+		suite := NewBLS12381SuiteWithExpander(exp, nil, nil)
+		msg := []byte("drand")
+		h1 := suite.G1().Point().(kyber.HashablePoint).Hash(msg)
+		require.True(t, h1.(GroupChecker).IsInCorrectGroup())
+		require.False(t, h1.Equal(NewBLS12381Suite().G1().Point().(kyber.HashablePoint).Hash(msg)))
+		h2 := suite.G2().Point().(kyber.HashablePoint).Hash(msg)
+		require.True(t, h2.(GroupChecker).IsInCorrectGroup())
+
+		test.SchemeTesting(t, bls.NewSchemeOnG2(suite))
+		test.SchemeTesting(t, bls.NewSchemeOnG1(suite))
+	}
+}
+
+func TestOversizeDomains(t *testing.T) {
+	dst := []byte(strings.Repeat("long DST ", 32))
+	msg := []byte("drand")
+	for _, b := range backends {
+		legacy := NewBLS12381SuiteWithDST(dst, dst, WithBackend(b))
+		rfc := NewBLS12381SuiteWithDST(dst, dst, WithBackend(b), WithOversizeDomains())
+
+		// the DST is used as is by default, as by kilic
+		expected, err := BackendKilic.g1().hashToCurve(msg, dst)
+		require.NoError(t, err)
+		h1 := legacy.G1().Point().(kyber.HashablePoint).Hash(msg).(*KyberG1)
+		require.Equal(t, expected.compressed(), h1.p.compressed(), b.String())
+		expected, err = BackendKilic.g2().hashToCurve(msg, dst)
+		require.NoError(t, err)
+		h2 := legacy.G2().Point().(kyber.HashablePoint).Hash(msg).(*KyberG2)
+		require.Equal(t, expected.compressed(), h2.p.compressed(), b.String())
+
+		// and hashed first with the option, which is kept by Clone and Set
+		expected, err = hashToCurveG1(b, msg, dst, ExpanderXMDSHA256)
+		require.NoError(t, err)
+		p1 := rfc.G1().Point().Clone()
+		h1 = legacy.G1().Point().Set(p1).(kyber.HashablePoint).Hash(msg).(*KyberG1)
+		require.Equal(t, expected.compressed(), h1.p.compressed(), b.String())
+		expected, err = hashToCurveG2(b, msg, dst, ExpanderXMDSHA256)
+		require.NoError(t, err)
+		h2 = rfc.G2().Point().(kyber.HashablePoint).Hash(msg).(*KyberG2)
+		require.Equal(t, expected.compressed(), h2.p.compressed(), b.String())
+
+		// the shorter DSTs are not affected
+		short := NewBLS12381Suite(WithBackend(b), WithOversizeDomains())
+		require.True(t, short.G1().Point().(kyber.HashablePoint).Hash(msg).Equal(NullKyberG1().Hash(msg)))
+	}
+}
@@ -0,0 +1,177 @@
+package bls
+
+import (
+	"math/big"
+)
+
+// fieldModulus is the characteristic p of the base field Fp of BLS12-381.
+var fieldModulus, _ = new(big.Int).SetString("1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffaaab", 16)
+
+// fpByteSize is the size of a big-endian encoded Fp element.
+const fpByteSize = 48
+
+// FieldModulus returns the characteristic p of the base field Fp of BLS12-381.
+func FieldModulus() *big.Int {
+	return new(big.Int).Set(fieldModulus)
+}
+
+// Fp2 is an element c0 + c1 * u of the quadratic extension Fp2 = Fp[u] / (u^2 + 1),
+// with both coefficients reduced modulo p.
+type Fp2 struct {
+	C0, C1 *big.Int
+}
+
+// Bytes returns the big-endian encoding c1 || c0 of the element, as used by the ZCash serialization. The
+// coordinates of KyberG2.Affine and KyberG2.FromAffine are written in the other order, c0 || c1. The
+// coefficients are reduced modulo p, so that negative or larger values are encoded as their residues.
+func (e *Fp2) Bytes() []byte {
+	return append(fpToBytes(e.C1), fpToBytes(e.C0)...)
+}
+
+// String returns the coefficients of the element in hexadecimal.
+func (e *Fp2) String() string {
+	return "(" + e.C0.Text(16) + ", " + e.C1.Text(16) + ")"
+}
+
+// fpToBytes returns the big-endian encoding of e reduced modulo p.
+func fpToBytes(e *big.Int) []byte {
+	return new(big.Int).Mod(e, fieldModulus).FillBytes(make([]byte, fpByteSize))
+}
+
+// The helpers below implement the base field and quadratic extension arithmetic on big.Int, for the
+// parts of hash to curve that are exposed for debugging and are not provided by kilic. They are not
+// constant time.
+
+func fpFromHex(s string) *big.Int {
+	e, ok := new(big.Int).SetString(s, 16)
+	if !ok {
+		panic("bls12-381: invalid field element constant " + s)
+	}
+	return e
+}
+
+func fpAdd(a, b *big.Int) *big.Int {
+	r := new(big.Int).Add(a, b)
+	return r.Mod(r, fieldModulus)
+}
+
+func fpSub(a, b *big.Int) *big.Int {
+	r := new(big.Int).Sub(a, b)
+	return r.Mod(r, fieldModulus)
+}
+
+func fpMul(a, b *big.Int) *big.Int {
+	r := new(big.Int).Mul(a, b)
+	return r.Mod(r, fieldModulus)
+}
+
+func fpNeg(a *big.Int) *big.Int {
+	r := new(big.Int).Neg(a)
+	return r.Mod(r, fieldModulus)
+}
+
+// fpInv0 returns the inverse of a, or 0 if a is 0, as inv0 in RFC 9380.
+func fpInv0(a *big.Int) *big.Int {
+	if a.Sign() == 0 {
+		return new(big.Int)
+	}
+	return new(big.Int).ModInverse(a, fieldModulus)
+}
+
+func fpIsSquare(a *big.Int) bool {
+	return big.Jacobi(a, fieldModulus) >= 0
+}
+
+// fpSqrt returns a square root of a, or nil if a is not a square.
+func fpSqrt(a *big.Int) *big.Int {
+	return new(big.Int).ModSqrt(a, fieldModulus)
+}
+
+func fpSgn0(a *big.Int) uint {
+	return a.Bit(0)
+}
+
+func newFp2(c0, c1 *big.Int) *Fp2 {
+	return &Fp2{C0: c0, C1: c1}
+}
+
+func fp2FromHex(c0, c1 string) *Fp2 {
+	return newFp2(fpFromHex(c0), fpFromHex(c1))
+}
+
+func (e *Fp2) isZero() bool {
+	return e.C0.Sign() == 0 && e.C1.Sign() == 0
+}
+
+func (e *Fp2) equal(f *Fp2) bool {
+	return e.C0.Cmp(f.C0) == 0 && e.C1.Cmp(f.C1) == 0
+}
+
+func fp2Add(a, b *Fp2) *Fp2 {
+	return newFp2(fpAdd(a.C0, b.C0), fpAdd(a.C1, b.C1))
+}
+
+func fp2Sub(a, b *Fp2) *Fp2 {
+	return newFp2(fpSub(a.C0, b.C0), fpSub(a.C1, b.C1))
+}
+
+func fp2Neg(a *Fp2) *Fp2 {
+	return newFp2(fpNeg(a.C0), fpNeg(a.C1))
+}
+
+func fp2Mul(a, b *Fp2) *Fp2 {
+	// (a0 + a1 u)(b0 + b1 u) = a0 b0 - a1 b1 + (a0 b1 + a1 b0) u
+	return newFp2(
+		fpSub(fpMul(a.C0, b.C0), fpMul(a.C1, b.C1)),
+		fpAdd(fpMul(a.C0, b.C1), fpMul(a.C1, b.C0)),
+	)
+}
+
+func fp2Square(a *Fp2) *Fp2 {
+	return fp2Mul(a, a)
+}
+
+// fp2Norm returns a0^2 + a1^2, the norm of a over Fp.
+func fp2Norm(a *Fp2) *big.Int {
+	return fpAdd(fpMul(a.C0, a.C0), fpMul(a.C1, a.C1))
+}
+
+// fp2Inv0 returns the inverse of a, or 0 if a is 0, as inv0 in RFC 9380.
+func fp2Inv0(a *Fp2) *Fp2 {
+	n := fpInv0(fp2Norm(a))
+	return newFp2(fpMul(a.C0, n), fpNeg(fpMul(a.C1, n)))
+}
+
+func fp2IsSquare(a *Fp2) bool {
+	return fpIsSquare(fp2Norm(a))
+}
+
+// fp2Sqrt returns a square root of a, or nil if a is not a square.
+func fp2Sqrt(a *Fp2) *Fp2 {
+	if a.C1.Sign() == 0 {
+		if r := fpSqrt(a.C0); r != nil {
+			return newFp2(r, new(big.Int))
+		}
+		// -1 is not a square in Fp, so a0 = -(r u)^2 with r = sqrt(-a0)
+		return newFp2(new(big.Int), fpSqrt(fpNeg(a.C0)))
+	}
+	n := fpSqrt(fp2Norm(a))
+	if n == nil {
+		return nil
+	}
+	half := new(big.Int).ModInverse(big.NewInt(2), fieldModulus)
+	// x0^2 = (a0 + n) / 2 or (a0 - n) / 2, whichever is a square, and x1 = a1 / (2 x0)
+	x0 := fpSqrt(fpMul(fpAdd(a.C0, n), half))
+	if x0 == nil {
+		x0 = fpSqrt(fpMul(fpSub(a.C0, n), half))
+	}
+	x1 := fpMul(a.C1, fpInv0(fpAdd(x0, x0)))
+	return newFp2(x0, x1)
+}
+
+func fp2Sgn0(a *Fp2) uint {
+	if a.C0.Sign() == 0 {
+		return a.C1.Bit(0)
+	}
+	return a.C0.Bit(0)
+}
@@ -0,0 +1,265 @@
+package bls
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/drand/kyber"
+	"github.com/drand/kyber/group/mod"
+	"github.com/stretchr/testify/require"
+)
+
+// compatibilityVector is an entry of the file written by tests/generator.
+type compatibilityVector struct {
+	Msg          string
+	Ciphersuite  string
+	G1Compressed []byte
+	G2Compressed []byte
+	BLSPrivKey   string
+	BLSPubKey    []byte
+	BLSSigG2     []byte
+}
+
+// compatibilityVectors loads the seed corpus shared by the fuzzers.
+func compatibilityVectors(f *testing.F) []compatibilityVector {
+	buf, err := os.ReadFile("tests/generator/compatibility.dat")
+	require.NoError(f, err)
+	var vectors []compatibilityVector
+	require.NoError(f, json.Unmarshal(buf, &vectors))
+	require.NotEmpty(f, vectors)
+	return vectors
+}
+
+// fuzzDecoding checks that decoding never panics, that rejected inputs leave the point unchanged and that
+// accepted inputs are canonical.
+func fuzzDecoding(t *testing.T, p GroupChecker, data []byte) {
+	before := p.Clone()
+	if err := p.UnmarshalBinary(data); err != nil {
+		require.True(t, p.Equal(before))
+		return
+	}
+	require.True(t, p.IsInCorrectGroup())
+	buf, err := p.MarshalBinary()
+	require.NoError(t, err)
+	require.Equal(t, data, buf)
+
+	q := p.Clone().(GroupChecker)
+	n, err := q.UnmarshalFrom(bytes.NewReader(data))
+	require.NoError(t, err)
+	require.Equal(t, len(data), n)
+	require.True(t, p.Equal(q))
+}
+
+func FuzzG1UnmarshalBinary(f *testing.F) {
+	for _, v := range compatibilityVectors(f) {
+		f.Add(v.G1Compressed)
+	}
+	f.Add([]byte{})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		fuzzDecoding(t, NullKyberG1(), data)
+	})
+}
+
+func FuzzG2UnmarshalBinary(f *testing.F) {
+	for _, v := range compatibilityVectors(f) {
+		f.Add(v.G2Compressed)
+		if v.BLSSigG2 != nil {
+			f.Add(v.BLSSigG2)
+		}
+	}
+	f.Add([]byte{})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		fuzzDecoding(t, NullKyberG2(), data)
+	})
+}
+
+func FuzzGTUnmarshalBinary(f *testing.F) {
+	suite := NewBLS12381Suite()
+	for _, v := range compatibilityVectors(f)[:5] {
+		g1, g2 := suite.G1().Point(), suite.G2().Point()
+		require.NoError(f, g1.UnmarshalBinary(v.G1Compressed))
+		require.NoError(f, g2.UnmarshalBinary(v.G2Compressed))
+		buf, err := suite.Pair(g1, g2).MarshalBinary()
+		require.NoError(f, err)
+		f.Add(buf)
+	}
+	f.Add([]byte{})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		fuzzDecoding(t, newEmptyGT(), data)
+	})
+}
+
+// Hash must always return a point of the group, matching the generic RFC 9380 implementation, except for the
+// tags longer than 255 bytes which are hashed as by kilic unless the point has oversize domains.
+func FuzzG1Hash(f *testing.F) {
+	for _, v := range compatibilityVectors(f) {
+		f.Add([]byte(v.Msg), []byte(v.Ciphersuite))
+	}
+	f.Add([]byte("abc"), bytes.Repeat([]byte("a"), 256))
+	f.Fuzz(func(t *testing.T, msg, dst []byte) {
+		p := NullKyberG1(dst...).Hash(msg).(*KyberG1)
+		require.True(t, p.IsInCorrectGroup())
+		oversize := NullKyberG1(dst...)
+		oversize.oversize = true
+		oversize.Hash(msg)
+		if len(dst) == 0 {
+			dst = DefaultDomainG1()
+		}
+		q, err := hashToCurveG1(p.backend, msg, dst, ExpanderXMDSHA256)
+		require.NoError(t, err)
+		require.True(t, oversize.p.equal(q))
+		if len(dst) > 255 {
+			q, err = BackendKilic.g1().hashToCurve(msg, dst)
+			require.NoError(t, err)
+		}
+		require.True(t, p.p.equal(q))
+	})
+}
+
+func FuzzG2Hash(f *testing.F) {
+	for _, v := range compatibilityVectors(f) {
+		f.Add([]byte(v.Msg), []byte(v.Ciphersuite))
+	}
+	f.Add([]byte("abc"), bytes.Repeat([]byte("a"), 256))
+	f.Fuzz(func(t *testing.T, msg, dst []byte) {
+		p := NullKyberG2(dst...).Hash(msg).(*KyberG2)
+		require.True(t, p.IsInCorrectGroup())
+		oversize := NullKyberG2(dst...)
+		oversize.oversize = true
+		oversize.Hash(msg)
+		if len(dst) == 0 {
+			dst = DefaultDomainG2()
+		}
+		q, err := hashToCurveG2(p.backend, msg, dst, ExpanderXMDSHA256)
+		require.NoError(t, err)
+		require.True(t, oversize.p.equal(q))
+		if len(dst) > 255 {
+			q, err = BackendKilic.g2().hashToCurve(msg, dst)
+			require.NoError(t, err)
+		}
+		require.True(t, p.p.equal(q))
+	})
+}
+
+// refPoint is an affine point of y^2 = x^3 + b over Fp2, with G1 embedded as the points with coordinates
+// in Fp. It is the slow reference the group arithmetic is compared against.
+type refPoint struct {
+	x, y *Fp2
+	inf  bool
+}
+
+func (p *refPoint) equal(q *refPoint) bool {
+	if p.inf || q.inf {
+		return p.inf == q.inf
+	}
+	return p.x.equal(q.x) && p.y.equal(q.y)
+}
+
+func refNeg(p *refPoint) *refPoint {
+	if p.inf {
+		return p
+	}
+	return &refPoint{x: p.x, y: fp2Neg(p.y)}
+}
+
+func refAdd(p, q *refPoint) *refPoint {
+	if p.inf {
+		return q
+	}
+	if q.inf {
+		return p
+	}
+	var lambda *Fp2
+	if p.x.equal(q.x) {
+		if !p.y.equal(q.y) || p.y.isZero() {
+			return &refPoint{inf: true}
+		}
+		// lambda = 3 x^2 / 2 y
+		x2 := fp2Square(p.x)
+		lambda = fp2Mul(fp2Add(fp2Add(x2, x2), x2), fp2Inv0(fp2Add(p.y, p.y)))
+	} else {
+		lambda = fp2Mul(fp2Sub(q.y, p.y), fp2Inv0(fp2Sub(q.x, p.x)))
+	}
+	x := fp2Sub(fp2Sub(fp2Square(lambda), p.x), q.x)
+	y := fp2Sub(fp2Mul(lambda, fp2Sub(p.x, x)), p.y)
+	return &refPoint{x: x, y: y}
+}
+
+func refMul(k *big.Int, p *refPoint) *refPoint {
+	r := &refPoint{inf: true}
+	for i := k.BitLen() - 1; i >= 0; i-- {
+		r = refAdd(r, r)
+		if k.Bit(i) == 1 {
+			r = refAdd(r, p)
+		}
+	}
+	return r
+}
+
+func refFromG1(p *KyberG1) *refPoint {
+	if p.p.equal(p.backend.g1().zero()) {
+		return &refPoint{inf: true}
+	}
+	raw := p.p.uncompressed()
+	zero := new(big.Int)
+	return &refPoint{
+		x: newFp2(new(big.Int).SetBytes(raw[:48]), zero),
+		y: newFp2(new(big.Int).SetBytes(raw[48:]), zero),
+	}
+}
+
+func refFromG2(p *KyberG2) *refPoint {
+	if p.p.equal(p.backend.g2().zero()) {
+		return &refPoint{inf: true}
+	}
+	raw := p.p.uncompressed()
+	return &refPoint{
+		x: newFp2(new(big.Int).SetBytes(raw[48:96]), new(big.Int).SetBytes(raw[:48])),
+		y: newFp2(new(big.Int).SetBytes(raw[144:]), new(big.Int).SetBytes(raw[96:144])),
+	}
+}
+
+// fuzzArithmetic compares Add, Sub, Neg and Mul on the decoded points with the reference implementation.
+func fuzzArithmetic(t *testing.T, a, b kyber.Point, ea, eb, es []byte, ref func(kyber.Point) *refPoint) {
+	if a.UnmarshalBinary(ea) != nil || b.UnmarshalBinary(eb) != nil {
+		return
+	}
+	s := NewKyberScalar().SetBytes(es)
+	ra, rb, k := ref(a), ref(b), s.(*mod.Int).V
+	require.True(t, refAdd(ra, rb).equal(ref(a.Clone().Add(a, b))), "Add")
+	require.True(t, refAdd(ra, refNeg(rb)).equal(ref(a.Clone().Sub(a, b))), "Sub")
+	require.True(t, refNeg(ra).equal(ref(a.Clone().Neg(a))), "Neg")
+	require.True(t, refAdd(ra, ra).equal(ref(a.Clone().Add(a, a))), "Add doubling")
+	require.True(t, refMul(&k, ra).equal(ref(a.Clone().Mul(s, a))), "Mul")
+}
+
+func FuzzG1Arithmetic(f *testing.F) {
+	vectors := compatibilityVectors(f)
+	for i := 0; i+1 < len(vectors) && i < 10; i++ {
+		f.Add(vectors[i].G1Compressed, vectors[i+1].G1Compressed, vectors[i+1].G2Compressed[:32])
+	}
+	f.Add(vectors[0].G1Compressed, vectors[0].G1Compressed, []byte{2})
+	f.Add(vectors[0].G1Compressed, vectors[0].G1Compressed, curveOrder.Bytes())
+	f.Fuzz(func(t *testing.T, ea, eb, es []byte) {
+		fuzzArithmetic(t, NullKyberG1(), NullKyberG1(), ea, eb, es, func(p kyber.Point) *refPoint {
+			return refFromG1(p.(*KyberG1))
+		})
+	})
+}
+
+func FuzzG2Arithmetic(f *testing.F) {
+	vectors := compatibilityVectors(f)
+	for i := 0; i+1 < len(vectors) && i < 10; i++ {
+		f.Add(vectors[i].G2Compressed, vectors[i+1].G2Compressed, vectors[i+1].G1Compressed[:32])
+	}
+	f.Add(vectors[0].G2Compressed, vectors[0].G2Compressed, []byte{2})
+	f.Add(vectors[0].G2Compressed, vectors[0].G2Compressed, curveOrder.Bytes())
+	f.Fuzz(func(t *testing.T, ea, eb, es []byte) {
+		fuzzArithmetic(t, NullKyberG2(), NullKyberG2(), ea, eb, es, func(p kyber.Point) *refPoint {
+			return refFromG2(p.(*KyberG2))
+		})
+	})
+}
@@ -3,15 +3,17 @@ module github.com/drand/kyber-bls12381
 go 1.25.0
 
 require (
+	github.com/consensys/gnark-crypto v0.19.2
 	github.com/drand/kyber v1.3.2
 	github.com/kilic/bls12-381 v0.1.0
 	github.com/stretchr/testify v1.11.1
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
+	github.com/bits-and-blooms/bitset v1.24.4 // indirect
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	golang.org/x/crypto v0.53.0 // indirect
 	golang.org/x/sys v0.46.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -0,0 +1,192 @@
+package groth16
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/drand/kyber"
+	bls "github.com/drand/kyber-bls12381"
+	"github.com/drand/kyber/pairing"
+)
+
+// The binary encodings of gnark, written by the WriteTo and WriteRawTo methods of the VerifyingKey and the
+// Proof of its backend/groth16/bls12-381 package, are sequences of points in the compressed or uncompressed
+// ZCash formats, and of slices prefixed by their big-endian uint32 length:
+//
+//	verifying key: [α]G1 [β]G1 [β]G2 [γ]G2 [δ]G1 [δ]G2 K []uint32-prefixed G1 points,
+//	               followed since gnark 0.9 by the commitment data: the public inputs committed to [][]uint64,
+//	               and the number of commitment keys uint32, followed by the keys, each G G2 and -σG G2
+//	proof:         A G1, B G2, C G1, followed since gnark 0.9 by the commitments []G1 and their proof G1
+//
+// A [][]uint64 is a uint32 number of slices, each a uint32 length followed by big-endian uint64 values.
+
+var (
+	errGnark           = errors.New("groth16: invalid gnark encoding")
+	errGnarkCommitment = errors.New("groth16: invalid gnark commitment data")
+)
+
+// maxGnarkLength bounds the allocations for the lengths read from the encodings, before the data is read.
+const maxGnarkLength = 1024
+
+// compressedFlag is the flag of the compressed ZCash encodings, in their first byte.
+const compressedFlag = 0x80
+
+// ReadGnarkVerifyingKey reads a verifying key of gnark, written by WriteTo or WriteRawTo. It reads the
+// encodings of gnark 0.8, which end with K, and of later versions, with the keys of the commitments of the
+// circuits using the Commit API.
+func ReadGnarkVerifyingKey(suite pairing.Suite, r io.Reader) (*VerifyingKey, error) {
+	br := bufio.NewReader(r)
+	g1, g2 := suite.G1(), suite.G2()
+	alpha, beta, gamma, delta := g1.Point(), g2.Point(), g2.Point(), g2.Point()
+	// [β]G1 and [δ]G1 are used by the prover only
+	for _, p := range []kyber.Point{alpha, g1.Point(), beta, gamma, g1.Point(), delta} {
+		if err := readGnarkPoint(br, p); err != nil {
+			return nil, err
+		}
+	}
+	n, err := readGnarkUint32(br)
+	if err != nil {
+		return nil, err
+	}
+	// the length comes from the input, and only bounds the allocation once the points are read
+	ic := make([]kyber.Point, 0, min(n, maxGnarkLength))
+	for i := uint32(0); i < n; i++ {
+		p := g1.Point()
+		if err := readGnarkPoint(br, p); err != nil {
+			return nil, err
+		}
+		ic = append(ic, p)
+	}
+
+	// the commitment data, absent before gnark 0.9
+	committed, err := readGnarkUint32(br)
+	if errors.Is(err, io.EOF) {
+		return NewVerifyingKey(suite, alpha, beta, gamma, delta, ic)
+	}
+	if err != nil {
+		return nil, err
+	}
+	publicCommitted := make([][]int, 0, min(committed, maxGnarkLength))
+	for i := uint32(0); i < committed; i++ {
+		m, err := readGnarkUint32(br)
+		if err != nil {
+			return nil, err
+		}
+		indices := make([]int, 0, min(m, maxGnarkLength))
+		for j := uint32(0); j < m; j++ {
+			var buf [8]byte
+			if _, err := io.ReadFull(br, buf[:]); err != nil {
+				return nil, fmt.Errorf("%w: %v", errGnark, err)
+			}
+			index := binary.BigEndian.Uint64(buf[:])
+			if index < 1 || index >= uint64(len(ic)) {
+				return nil, fmt.Errorf("%w: committed input %d", errGnarkCommitment, index)
+			}
+			indices = append(indices, int(index))
+		}
+		publicCommitted = append(publicCommitted, indices)
+	}
+	keys, err := readGnarkUint32(br)
+	if err != nil {
+		return nil, err
+	}
+	if keys != committed || int(keys) >= len(ic) {
+		return nil, fmt.Errorf("%w: %d commitment keys for %d commitments", errGnarkCommitment, keys, committed)
+	}
+	commitmentKeys := make([]CommitmentKey, keys)
+	for i := range commitmentKeys {
+		commitmentKeys[i] = CommitmentKey{G: g2.Point(), GSigmaNeg: g2.Point()}
+		for _, p := range []kyber.Point{commitmentKeys[i].G, commitmentKeys[i].GSigmaNeg} {
+			if err := readGnarkPoint(br, p); err != nil {
+				return nil, err
+			}
+		}
+		if !commitmentKeys[i].G.Equal(commitmentKeys[0].G) {
+			return nil, fmt.Errorf("%w: commitment keys with different G", errGnarkCommitment)
+		}
+	}
+	vk, err := NewVerifyingKey(suite, alpha, beta, gamma, delta, ic)
+	if err != nil {
+		return nil, err
+	}
+	vk.CommitmentKeys, vk.PublicCommitted = commitmentKeys, publicCommitted
+	// a commitment may commit to the public inputs and to the hashes of the previous commitments
+	for i, indices := range publicCommitted {
+		for _, j := range indices {
+			if j > vk.NumPublic()+i {
+				return nil, fmt.Errorf("%w: committed input %d", errGnarkCommitment, j)
+			}
+		}
+	}
+	return vk, nil
+}
+
+// ReadGnarkProof reads a proof of gnark, written by WriteTo or WriteRawTo. It reads the encodings of gnark
+// 0.8, which end with C, and of later versions, with the commitments of the circuits using the Commit API.
+func ReadGnarkProof(suite pairing.Suite, r io.Reader) (*Proof, error) {
+	br := bufio.NewReader(r)
+	proof := &Proof{A: suite.G1().Point(), B: suite.G2().Point(), C: suite.G1().Point()}
+	for _, p := range []kyber.Point{proof.A, proof.B, proof.C} {
+		if err := readGnarkPoint(br, p); err != nil {
+			return nil, err
+		}
+	}
+	n, err := readGnarkUint32(br)
+	if errors.Is(err, io.EOF) {
+		return proof, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	commitments := make([]kyber.Point, 0, min(n, maxGnarkLength))
+	for i := uint32(0); i < n; i++ {
+		p := suite.G1().Point()
+		if err := readGnarkPoint(br, p); err != nil {
+			return nil, err
+		}
+		commitments = append(commitments, p)
+	}
+	// the proof of knowledge of the commitments, the point at infinity without them
+	pok := suite.G1().Point()
+	if err := readGnarkPoint(br, pok); err != nil {
+		return nil, err
+	}
+	if n > 0 {
+		proof.Commitments, proof.CommitmentPok = commitments, pok
+	}
+	return proof, nil
+}
+
+// readGnarkPoint sets p from its compressed or uncompressed encoding, which has the compressed flag.
+func readGnarkPoint(r io.Reader, p kyber.Point) error {
+	size := p.MarshalSize()
+	buf := make([]byte, 2*size)
+	if _, err := io.ReadFull(r, buf[:size]); err != nil {
+		return fmt.Errorf("%w: %v", errGnark, err)
+	}
+	if buf[0]&compressedFlag != 0 {
+		if err := p.UnmarshalBinary(buf[:size]); err != nil {
+			return fmt.Errorf("%w: %v", errGnark, err)
+		}
+		return nil
+	}
+	if _, err := io.ReadFull(r, buf[size:]); err != nil {
+		return fmt.Errorf("%w: %v", errGnark, err)
+	}
+	if err := bls.ProfileZCashUncompressed.Unmarshal(p, buf); err != nil {
+		return fmt.Errorf("%w: %v", errGnark, err)
+	}
+	return nil
+}
+
+// readGnarkUint32 reads a length, returning an error wrapping io.EOF if r has no more data.
+func readGnarkUint32(r io.Reader) (uint32, error) {
+	var buf [4]byte
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		return 0, fmt.Errorf("%w: %w", errGnark, err)
+	}
+	return binary.BigEndian.Uint32(buf[:]), nil
+}
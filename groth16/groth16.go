@@ -0,0 +1,252 @@
+// Package groth16 verifies Groth16 proofs on BLS12-381, such as the proofs of gnark, circom and snarkjs.
+// Verifying keys and proofs are read from the JSON files of snarkjs, or from the binary encodings of gnark.
+//
+// A proof (A, B, C) of the public inputs x_1...x_n is valid if e(A, B) = e(α, β) e(L, γ) e(C, δ), with
+// L = IC_0 + x_1 IC_1 + ... + x_n IC_n, which is checked as a product of four pairings equal to one.
+//
+// The circuits of gnark using its Commit API have verifying keys with commitment keys, and proofs with
+// commitments D_1...D_m in G1 and a proof of knowledge of their openings. The hashes of the commitments, and
+// of the public inputs they commit to, follow the public inputs, and L is IC_0 + x_1 IC_1 + ... + x_n IC_n +
+// h_1 IC_n+1 + ... + h_m IC_n+m + D_1 + ... + D_m.
+package groth16
+
+import (
+	"crypto/cipher"
+	"errors"
+	"fmt"
+
+	"github.com/drand/kyber"
+	bls "github.com/drand/kyber-bls12381"
+	"github.com/drand/kyber/pairing"
+)
+
+// ErrInvalidProof is returned when a proof does not verify.
+var ErrInvalidProof = errors.New("groth16: invalid proof")
+
+var (
+	errKey          = errors.New("groth16: invalid verifying key")
+	errLength       = errors.New("groth16: inputs of different lengths")
+	errPublicInputs = errors.New("groth16: wrong number of public inputs")
+	errCommitments  = errors.New("groth16: wrong number of commitments")
+)
+
+// The DSTs of gnark for the hashes of the commitments and for the challenge of their proof of knowledge.
+var (
+	commitmentDST = []byte("bsb22-commitment")
+	challengeDST  = []byte("G16-BSB22")
+)
+
+// VerifyingKey is a Groth16 verifying key. Its points must be in the prime order subgroups, which the
+// functions reading them check.
+type VerifyingKey struct {
+	suite pairing.Suite
+	// Alpha is [α]G1.
+	Alpha kyber.Point
+	// Beta, Gamma and Delta are [β]G2, [γ]G2 and [δ]G2.
+	Beta, Gamma, Delta kyber.Point
+	// IC holds the points of G1 of the public inputs, starting with the one of the constant 1, i.e. one more
+	// than the number of public inputs. It is the IC of snarkjs, and K of gnark.
+	IC []kyber.Point
+	// CommitmentKeys holds the keys of the commitments of gnark, the last len(CommitmentKeys) points of IC
+	// being those of their hashes. Their G must be the same.
+	CommitmentKeys []CommitmentKey
+	// PublicCommitted holds for each commitment the indices, from 1, of the public inputs it commits to,
+	// followed by the hashes of the previous commitments.
+	PublicCommitted [][]int
+
+	// -β, -γ and -δ, prepared for the product of pairings
+	negBeta, negGamma, negDelta kyber.Point
+}
+
+// CommitmentKey is the key of a Pedersen commitment of gnark, with G and -σG in G2.
+type CommitmentKey struct {
+	G, GSigmaNeg kyber.Point
+}
+
+// Proof is a Groth16 proof, with A and C in G1, and B in G2. Its points must be in the prime order subgroups,
+// which the functions reading them check.
+type Proof struct {
+	A, B, C kyber.Point
+	// Commitments holds the commitments of gnark, one for each commitment key, and CommitmentPok the proof of
+	// knowledge of their openings, in G1.
+	Commitments   []kyber.Point
+	CommitmentPok kyber.Point
+}
+
+// NewVerifyingKey returns the verifying key with the given points, for the pairings of suite.
+func NewVerifyingKey(suite pairing.Suite, alpha, beta, gamma, delta kyber.Point, ic []kyber.Point) (*VerifyingKey, error) {
+	if len(ic) == 0 {
+		return nil, fmt.Errorf("%w: no point for the constant input", errKey)
+	}
+	g2 := suite.G2()
+	return &VerifyingKey{
+		suite:    suite,
+		Alpha:    alpha,
+		Beta:     beta,
+		Gamma:    gamma,
+		Delta:    delta,
+		IC:       ic,
+		negBeta:  g2.Point().Neg(beta),
+		negGamma: g2.Point().Neg(gamma),
+		negDelta: g2.Point().Neg(delta),
+	}, nil
+}
+
+// NumPublic returns the number of public inputs of the circuit.
+func (vk *VerifyingKey) NumPublic() int {
+	return len(vk.IC) - 1 - len(vk.CommitmentKeys)
+}
+
+// Verify checks the proof of the public inputs, returning ErrInvalidProof if it does not verify, and another
+// error if the number of public inputs is wrong.
+func (vk *VerifyingKey) Verify(proof *Proof, public []kyber.Scalar) error {
+	if len(public) != vk.NumPublic() {
+		return fmt.Errorf("%w: %d instead of %d", errPublicInputs, len(public), vk.NumPublic())
+	}
+	g1 := vk.suite.G1()
+	hashes, err := vk.commitmentHashes(proof, public)
+	if err != nil {
+		return err
+	}
+	if len(hashes) > 0 {
+		ps, qs := vk.pokPairings(proof, hashes, g1.Scalar().One())
+		if !bls.CheckPairings(vk.suite, ps, qs) {
+			return ErrInvalidProof
+		}
+	}
+	ss := make([]kyber.Scalar, 0, len(vk.IC))
+	ss = append(append(append(ss, g1.Scalar().One()), public...), hashes...)
+	l := bls.LinearCombination(g1.Point(), vk.IC, ss)
+	for _, d := range proof.Commitments {
+		l.Add(l, d)
+	}
+	// e(A, B) e(α, -β) e(L, -γ) e(C, -δ) = 1
+	if !bls.CheckPairings(vk.suite,
+		[]kyber.Point{proof.A, vk.Alpha, l, proof.C},
+		[]kyber.Point{proof.B, vk.negBeta, vk.negGamma, vk.negDelta},
+	) {
+		return ErrInvalidProof
+	}
+	return nil
+}
+
+// VerifyBatch checks the proofs of the public inputs public[i], returning ErrInvalidProof if one of them does
+// not verify. The proofs are combined with random scalars from rand, for a cost of len(proofs) + 3 pairings,
+// and len(CommitmentKeys) + 1 more for the proofs of knowledge of the commitments.
+func (vk *VerifyingKey) VerifyBatch(proofs []*Proof, public [][]kyber.Scalar, rand cipher.Stream) error {
+	if len(proofs) != len(public) {
+		return errLength
+	}
+	if len(proofs) == 0 {
+		return nil
+	}
+	g1 := vk.suite.G1()
+	// Π e(r_i A_i, B_i) e(Σ r_i α, -β) e(Σ r_i L_i, -γ) e(Σ r_i C_i, -δ) = 1, the sum of the r_i L_i being
+	// the combination of the IC_j with the scalars Σ r_i x_ij, x_i0 being 1 and the last x_ij the hashes of
+	// the commitments, plus the combination of the commitments D_ij with the r_i
+	rs := make([]kyber.Scalar, len(proofs))
+	cs := make([]kyber.Point, len(proofs))
+	ss := make([]kyber.Scalar, len(vk.IC))
+	for j := range ss {
+		ss[j] = g1.Scalar().Zero()
+	}
+	var ds []kyber.Point
+	var dr []kyber.Scalar
+	// the pairings of the proofs of knowledge of the commitments, with the same points of G2 for every proof,
+	// combined with other random scalars
+	var pokPs, pokQs []kyber.Point
+	ps := make([]kyber.Point, 0, len(proofs)+3)
+	qs := make([]kyber.Point, 0, len(proofs)+3)
+	for i, proof := range proofs {
+		if len(public[i]) != vk.NumPublic() {
+			return fmt.Errorf("%w: %d instead of %d", errPublicInputs, len(public[i]), vk.NumPublic())
+		}
+		hashes, err := vk.commitmentHashes(proof, public[i])
+		if err != nil {
+			return err
+		}
+		rs[i] = g1.Scalar().Pick(rand)
+		ss[0].Add(ss[0], rs[i])
+		for j, x := range append(append([]kyber.Scalar(nil), public[i]...), hashes...) {
+			ss[j+1].Add(ss[j+1], g1.Scalar().Mul(rs[i], x))
+		}
+		for _, d := range proof.Commitments {
+			ds, dr = append(ds, d), append(dr, rs[i])
+		}
+		if len(hashes) > 0 {
+			pps, pqs := vk.pokPairings(proof, hashes, g1.Scalar().Pick(rand))
+			if pokPs == nil {
+				pokPs, pokQs = pps, pqs
+			} else {
+				for j, p := range pps {
+					pokPs[j].Add(pokPs[j], p)
+				}
+			}
+		}
+		cs[i] = proof.C
+		ps = append(ps, g1.Point().Mul(rs[i], proof.A))
+		qs = append(qs, proof.B)
+	}
+	l := bls.LinearCombination(g1.Point(), vk.IC, ss)
+	if len(ds) > 0 {
+		l.Add(l, bls.LinearCombination(g1.Point(), ds, dr))
+	}
+	ps = append(ps, g1.Point().Mul(ss[0], vk.Alpha), l, bls.LinearCombination(g1.Point(), cs, rs))
+	qs = append(qs, vk.negBeta, vk.negGamma, vk.negDelta)
+	if !bls.CheckPairings(vk.suite, append(ps, pokPs...), append(qs, pokQs...)) {
+		return ErrInvalidProof
+	}
+	return nil
+}
+
+// commitmentHashes returns the hashes of the commitments of proof, each followed by the inputs it commits to,
+// as gnark computes them: hash_to_field into the scalars with the DST "bsb22-commitment", of
+// the uncompressed encoding of the commitment and of the 32-byte encodings of the inputs.
+func (vk *VerifyingKey) commitmentHashes(proof *Proof, public []kyber.Scalar) ([]kyber.Scalar, error) {
+	if len(proof.Commitments) != len(vk.CommitmentKeys) || len(proof.Commitments) > 0 && proof.CommitmentPok == nil {
+		return nil, fmt.Errorf("%w: %d instead of %d", errCommitments, len(proof.Commitments), len(vk.CommitmentKeys))
+	}
+	if len(vk.PublicCommitted) != len(vk.CommitmentKeys) {
+		return nil, fmt.Errorf("%w: %d lists of committed inputs for %d commitment keys", errKey,
+			len(vk.PublicCommitted), len(vk.CommitmentKeys))
+	}
+	inputs := append([]kyber.Scalar(nil), public...)
+	for i, d := range proof.Commitments {
+		buf, err := bls.ProfileZCashUncompressed.Marshal(d)
+		if err != nil {
+			return nil, err
+		}
+		for _, j := range vk.PublicCommitted[i] {
+			if j < 1 || j > len(inputs) {
+				return nil, fmt.Errorf("%w: committed input %d of %d", errKey, j, len(inputs))
+			}
+			x, err := inputs[j-1].MarshalBinary()
+			if err != nil {
+				return nil, err
+			}
+			buf = append(buf, x...)
+		}
+		inputs = append(inputs, bls.HashToScalar(buf, commitmentDST))
+	}
+	return inputs[len(public):], nil
+}
+
+// pokPairings returns the pairings of the proof of knowledge of the commitments of proof, whose product is
+// one, each raised to t: e(t c^k D_k, -σG_k) for the commitments D_k, and e(t pok, G). The challenge c is
+// the hash_to_field of the hashes of the commitments with the DST "G16-BSB22", as in gnark.
+func (vk *VerifyingKey) pokPairings(proof *Proof, hashes []kyber.Scalar, t kyber.Scalar) (ps, qs []kyber.Point) {
+	g1 := vk.suite.G1()
+	var buf []byte
+	for _, h := range hashes {
+		x, _ := h.MarshalBinary()
+		buf = append(buf, x...)
+	}
+	c := bls.HashToScalar(buf, challengeDST)
+	ck := t.Clone()
+	for k, d := range proof.Commitments {
+		ps = append(ps, g1.Point().Mul(ck, d))
+		qs = append(qs, vk.CommitmentKeys[k].GSigmaNeg)
+		ck = g1.Scalar().Mul(ck, c)
+	}
+	return append(ps, g1.Point().Mul(t, proof.CommitmentPok)), append(qs, vk.CommitmentKeys[0].G)
+}
@@ -0,0 +1,346 @@
+package groth16
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/drand/kyber"
+	bls "github.com/drand/kyber-bls12381"
+	"github.com/drand/kyber/pairing"
+	"github.com/drand/kyber/util/random"
+	"github.com/stretchr/testify/require"
+)
+
+// The files under testdata hold a verifying key, a proof and its public inputs in the formats of snarkjs and
+// gnark, see testdata/README.md.
+
+var backends = []bls.Backend{bls.BackendKilic, bls.BackendGnark}
+
+// trapdoor simulates proofs of a random circuit knowing its trapdoor: the proof (a G1, b G2, c G1) is valid
+// if ab = αβ + γ(u_0 + Σ x_i u_i) + cδ, IC_i being u_i G1.
+type trapdoor struct {
+	suite                     pairing.Suite
+	alpha, beta, gamma, delta kyber.Scalar
+	u                         []kyber.Scalar
+	vk                        *VerifyingKey
+}
+
+func newTrapdoor(t *testing.T, suite pairing.Suite, public int) *trapdoor {
+	g1, g2 := suite.G1(), suite.G2()
+	pick := func() kyber.Scalar { return g1.Scalar().Pick(random.New()) }
+	td := &trapdoor{suite: suite, alpha: pick(), beta: pick(), gamma: pick(), delta: pick()}
+	ic := make([]kyber.Point, public+1)
+	for i := range ic {
+		td.u = append(td.u, pick())
+		ic[i] = g1.Point().Mul(td.u[i], nil)
+	}
+	var err error
+	td.vk, err = NewVerifyingKey(suite, g1.Point().Mul(td.alpha, nil), g2.Point().Mul(td.beta, nil),
+		g2.Point().Mul(td.gamma, nil), g2.Point().Mul(td.delta, nil), ic)
+	require.NoError(t, err)
+	return td
+}
+
+func (td *trapdoor) prove(public []kyber.Scalar) *Proof {
+	g1, g2 := td.suite.G1(), td.suite.G2()
+	a, b := g1.Scalar().Pick(random.New()), g1.Scalar().Pick(random.New())
+	l := td.u[0].Clone()
+	for i, x := range public {
+		l.Add(l, g1.Scalar().Mul(x, td.u[i+1]))
+	}
+	c := g1.Scalar().Mul(a, b)
+	c.Sub(c, g1.Scalar().Mul(td.alpha, td.beta))
+	c.Sub(c, g1.Scalar().Mul(td.gamma, l))
+	c.Div(c, td.delta)
+	return &Proof{A: g1.Point().Mul(a, nil), B: g2.Point().Mul(b, nil), C: g1.Point().Mul(c, nil)}
+}
+
+func randomInputs(g kyber.Group, n int) []kyber.Scalar {
+	xs := make([]kyber.Scalar, n)
+	for i := range xs {
+		xs[i] = g.Scalar().Pick(random.New())
+	}
+	return xs
+}
+
+func TestVerify(t *testing.T) {
+	for _, b := range backends {
+		t.Run(b.String(), func(t *testing.T) {
+			suite := bls.NewBLS12381Suite(bls.WithBackend(b))
+			g1 := suite.G1()
+			for _, n := range []int{0, 1, 5} {
+				td := newTrapdoor(t, suite, n)
+				require.Equal(t, n, td.vk.NumPublic())
+				public := randomInputs(g1, n)
+				proof := td.prove(public)
+				require.NoError(t, td.vk.Verify(proof, public))
+
+				wrong := &Proof{A: proof.A, B: proof.B, C: g1.Point().Add(proof.C, g1.Point().Base())}
+				require.ErrorIs(t, td.vk.Verify(wrong, public), ErrInvalidProof)
+				if n > 0 {
+					other := randomInputs(g1, n)
+					require.ErrorIs(t, td.vk.Verify(proof, other), ErrInvalidProof)
+				}
+				err := td.vk.Verify(proof, randomInputs(g1, n+1))
+				require.Error(t, err)
+				require.NotErrorIs(t, err, ErrInvalidProof)
+			}
+		})
+	}
+}
+
+func TestVerifyBatch(t *testing.T) {
+	suite := bls.NewBLS12381Suite()
+	g1 := suite.G1()
+	td := newTrapdoor(t, suite, 3)
+	var proofs []*Proof
+	var public [][]kyber.Scalar
+	for i := 0; i < 4; i++ {
+		public = append(public, randomInputs(g1, 3))
+		proofs = append(proofs, td.prove(public[i]))
+	}
+	require.NoError(t, td.vk.VerifyBatch(proofs, public, random.New()))
+	require.NoError(t, td.vk.VerifyBatch(proofs[:1], public[:1], random.New()))
+	require.NoError(t, td.vk.VerifyBatch(nil, nil, random.New()))
+
+	// proofs of swapped inputs verify neither alone nor in batch
+	public[1], public[2] = public[2], public[1]
+	require.ErrorIs(t, td.vk.VerifyBatch(proofs, public, random.New()), ErrInvalidProof)
+	require.Error(t, td.vk.VerifyBatch(proofs, public[:3], random.New()))
+	public[1] = public[1][:2]
+	require.Error(t, td.vk.VerifyBatch(proofs, public, random.New()))
+}
+
+// suiteWithoutPairingCheck hides the PairingCheck method of the suite.
+type suiteWithoutPairingCheck struct {
+	pairing.Suite
+}
+
+func TestVerifyWithPairings(t *testing.T) {
+	td := newTrapdoor(t, suiteWithoutPairingCheck{bls.NewBLS12381Suite()}, 2)
+	public := randomInputs(td.suite.G1(), 2)
+	proof := td.prove(public)
+	require.NoError(t, td.vk.Verify(proof, public))
+	proof.A = proof.C
+	require.ErrorIs(t, td.vk.Verify(proof, public), ErrInvalidProof)
+}
+
+func readFile(t *testing.T, name string) []byte {
+	buf, err := os.ReadFile("testdata/" + name)
+	require.NoError(t, err)
+	return buf
+}
+
+func TestSnarkJS(t *testing.T) {
+	for _, b := range backends {
+		t.Run(b.String(), func(t *testing.T) {
+			suite := bls.NewBLS12381Suite(bls.WithBackend(b))
+			vk, err := ReadSnarkJSVerifyingKey(suite, bytes.NewReader(readFile(t, "verification_key.json")))
+			require.NoError(t, err)
+			require.Equal(t, 2, vk.NumPublic())
+			proof, err := ReadSnarkJSProof(suite, bytes.NewReader(readFile(t, "proof.json")))
+			require.NoError(t, err)
+			public, err := ReadSnarkJSPublic(suite, bytes.NewReader(readFile(t, "public.json")))
+			require.NoError(t, err)
+			require.NoError(t, vk.Verify(proof, public))
+			public[1] = suite.G1().Scalar().Add(public[1], suite.G1().Scalar().One())
+			require.ErrorIs(t, vk.Verify(proof, public), ErrInvalidProof)
+		})
+	}
+}
+
+func TestSnarkJSErrors(t *testing.T) {
+	suite := bls.NewBLS12381Suite()
+	edit := func(name string, f func(m map[string]interface{})) []byte {
+		var m map[string]interface{}
+		require.NoError(t, json.Unmarshal(readFile(t, name), &m))
+		f(m)
+		buf, err := json.Marshal(m)
+		require.NoError(t, err)
+		return buf
+	}
+	// x + p, the non canonical coordinate of the same point
+	p, _ := new(big.Int).SetString("1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffff"+
+		"b9feffffffffaaab", 16)
+	var key struct {
+		Alpha []string `json:"vk_alpha_1"`
+	}
+	require.NoError(t, json.Unmarshal(readFile(t, "verification_key.json"), &key))
+	x, _ := new(big.Int).SetString(key.Alpha[0], 10)
+	nonCanonical := x.Add(x, p).String()
+	for name, buf := range map[string][]byte{
+		"protocol":      edit("verification_key.json", func(m map[string]interface{}) { m["protocol"] = "plonk" }),
+		"curve":         edit("verification_key.json", func(m map[string]interface{}) { m["curve"] = "bn128" }),
+		"nPublic":       edit("verification_key.json", func(m map[string]interface{}) { m["nPublic"] = 3.0 }),
+		"projective":    edit("verification_key.json", func(m map[string]interface{}) { m["vk_alpha_1"].([]interface{})[2] = "2" }),
+		"not on curve":  edit("verification_key.json", func(m map[string]interface{}) { m["vk_alpha_1"].([]interface{})[1] = "1" }),
+		"non canonical": edit("verification_key.json", func(m map[string]interface{}) { m["vk_alpha_1"].([]interface{})[0] = nonCanonical }),
+		"G2 point":      edit("verification_key.json", func(m map[string]interface{}) { m["vk_beta_2"] = m["vk_beta_2"].([]interface{})[:2] }),
+		"json":          []byte("{"),
+	} {
+		_, err := ReadSnarkJSVerifyingKey(suite, bytes.NewReader(buf))
+		require.Error(t, err, name)
+	}
+
+	proof := edit("proof.json", func(m map[string]interface{}) {
+		// swapping the coordinates of Fp2 gives a point which is not on the curve
+		x := m["pi_b"].([]interface{})[0].([]interface{})
+		x[0], x[1] = x[1], x[0]
+	})
+	_, err := ReadSnarkJSProof(suite, bytes.NewReader(proof))
+	require.Error(t, err)
+	proof = edit("proof.json", func(m map[string]interface{}) { m["pi_c"] = []string{"0", "1", "0"} })
+	infinity, err := ReadSnarkJSProof(suite, bytes.NewReader(proof))
+	require.NoError(t, err)
+	require.True(t, infinity.C.Equal(suite.G1().Point().Null()))
+
+	for _, public := range []string{`["-1"]`, `["0x1"]`, `[1]`,
+		`["52435875175126190479447740508185965837690552500527637822603658699938581184513"]`} {
+		_, err := ReadSnarkJSPublic(suite, strings.NewReader(public))
+		require.Error(t, err, public)
+	}
+}
+
+func TestGnark(t *testing.T) {
+	suite := bls.NewBLS12381Suite()
+	public, err := ReadSnarkJSPublic(suite, bytes.NewReader(readFile(t, "public.json")))
+	require.NoError(t, err)
+	reference, err := ReadSnarkJSVerifyingKey(suite, bytes.NewReader(readFile(t, "verification_key.json")))
+	require.NoError(t, err)
+	for _, suffix := range []string{"", "_raw"} {
+		t.Run("encoding"+suffix, func(t *testing.T) {
+			key := readFile(t, "vk"+suffix+".bin")
+			vk, err := ReadGnarkVerifyingKey(suite, bytes.NewReader(key))
+			require.NoError(t, err)
+			require.True(t, vk.Alpha.Equal(reference.Alpha))
+			require.True(t, vk.Delta.Equal(reference.Delta))
+			require.Len(t, vk.IC, len(reference.IC))
+			proofBuf := readFile(t, "proof"+suffix+".bin")
+			proof, err := ReadGnarkProof(suite, bytes.NewReader(proofBuf))
+			require.NoError(t, err)
+			require.NoError(t, vk.Verify(proof, public))
+			require.NoError(t, reference.Verify(proof, public))
+
+			// the encodings of gnark 0.8 end with K and C
+			g1Size := suite.G1().PointLen()
+			if suffix != "" {
+				g1Size *= 2
+			}
+			old := key[:len(key)-8]
+			vk, err = ReadGnarkVerifyingKey(suite, bytes.NewReader(old))
+			require.NoError(t, err)
+			require.Len(t, vk.IC, len(reference.IC))
+			oldProof := proofBuf[:len(proofBuf)-4-g1Size]
+			proof, err = ReadGnarkProof(suite, bytes.NewReader(oldProof))
+			require.NoError(t, err)
+			require.NoError(t, vk.Verify(proof, public))
+
+			// truncated encodings
+			for _, n := range []int{1, 5, 9} {
+				_, err = ReadGnarkVerifyingKey(suite, bytes.NewReader(key[:len(key)-n]))
+				require.Error(t, err, "key without %d bytes", n)
+				_, err = ReadGnarkProof(suite, bytes.NewReader(proofBuf[:len(proofBuf)-n]))
+				require.Error(t, err, "proof without %d bytes", n)
+			}
+
+			// commitments
+			withKeys := append([]byte(nil), key...)
+			withKeys[len(withKeys)-1] = 1
+			_, err = ReadGnarkVerifyingKey(suite, bytes.NewReader(withKeys))
+			require.Error(t, err)
+			withCommitments := append([]byte(nil), proofBuf...)
+			withCommitments[len(oldProof)+3] = 1
+			_, err = ReadGnarkProof(suite, bytes.NewReader(withCommitments))
+			require.Error(t, err)
+		})
+	}
+}
+
+// The proofs under testdata/gnark are proved by gnark, which also writes their keys.
+func TestGnarkProver(t *testing.T) {
+	for _, b := range backends {
+		suite := bls.NewBLS12381Suite(bls.WithBackend(b))
+		for _, suffix := range []string{"", "_raw"} {
+			t.Run(b.String()+"/encoding"+suffix, func(t *testing.T) {
+				public, err := ReadSnarkJSPublic(suite, bytes.NewReader(readFile(t, "gnark/cubic_public.json")))
+				require.NoError(t, err)
+				vk, err := ReadGnarkVerifyingKey(suite, bytes.NewReader(readFile(t, "gnark/cubic_vk"+suffix+".bin")))
+				require.NoError(t, err)
+				require.Equal(t, 2, vk.NumPublic())
+				proof, err := ReadGnarkProof(suite, bytes.NewReader(readFile(t, "gnark/cubic_proof"+suffix+".bin")))
+				require.NoError(t, err)
+				require.NoError(t, vk.Verify(proof, public))
+				public[0].Add(public[0], suite.G1().Scalar().One())
+				require.ErrorIs(t, vk.Verify(proof, public), ErrInvalidProof)
+
+				// the keys and proofs of circuits with commitments
+				public, err = ReadSnarkJSPublic(suite, bytes.NewReader(readFile(t, "gnark/committed_public.json")))
+				require.NoError(t, err)
+				vk, err = ReadGnarkVerifyingKey(suite, bytes.NewReader(readFile(t, "gnark/committed_vk"+suffix+".bin")))
+				require.NoError(t, err)
+				require.Equal(t, 2, vk.NumPublic())
+				require.Len(t, vk.CommitmentKeys, 1)
+				require.Equal(t, [][]int{{1}}, vk.PublicCommitted)
+				proof, err = ReadGnarkProof(suite, bytes.NewReader(readFile(t, "gnark/committed_proof"+suffix+".bin")))
+				require.NoError(t, err)
+				require.Len(t, proof.Commitments, 1)
+				require.NoError(t, vk.Verify(proof, public))
+				require.NoError(t, vk.VerifyBatch([]*Proof{proof, proof}, [][]kyber.Scalar{public, public}, random.New()))
+
+				// y is committed to, z is not
+				for i := range public {
+					wrong := append([]kyber.Scalar(nil), public...)
+					wrong[i] = suite.G1().Scalar().Add(public[i], suite.G1().Scalar().One())
+					require.ErrorIs(t, vk.Verify(proof, wrong), ErrInvalidProof)
+					require.ErrorIs(t, vk.VerifyBatch([]*Proof{proof, proof}, [][]kyber.Scalar{public, wrong}, random.New()),
+						ErrInvalidProof)
+				}
+				g := suite.G1().Point().Base()
+				for _, wrong := range []*Proof{
+					{A: proof.A, B: proof.B, C: proof.C, Commitments: []kyber.Point{g}, CommitmentPok: proof.CommitmentPok},
+					{A: proof.A, B: proof.B, C: proof.C, Commitments: proof.Commitments, CommitmentPok: g},
+				} {
+					require.ErrorIs(t, vk.Verify(wrong, public), ErrInvalidProof)
+					require.ErrorIs(t, vk.VerifyBatch([]*Proof{proof, wrong}, [][]kyber.Scalar{public, public}, random.New()),
+						ErrInvalidProof)
+				}
+				require.ErrorIs(t, vk.Verify(&Proof{A: proof.A, B: proof.B, C: proof.C}, public), errCommitments)
+			})
+		}
+	}
+}
+
+func TestGnarkCommitmentErrors(t *testing.T) {
+	suite := bls.NewBLS12381Suite()
+	key := readFile(t, "gnark/committed_vk.bin")
+	g2Size := suite.G2().PointLen()
+	// the commitment data is [][]uint64{{1}}, one key, then the key
+	data := len(key) - 2*g2Size - 4 - 8 - 4 - 4
+	require.Equal(t, []byte{0, 0, 0, 1, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 1}, key[data:data+20])
+	for _, tc := range []struct {
+		name   string
+		offset int
+		value  byte
+	}{
+		{"input 0", data + 15, 0},
+		{"input out of range", data + 15, 3},
+		{"no key", data + 19, 0},
+		{"two keys", data + 19, 2},
+	} {
+		bad := append([]byte(nil), key...)
+		bad[tc.offset] = tc.value
+		_, err := ReadGnarkVerifyingKey(suite, bytes.NewReader(bad))
+		require.ErrorIs(t, err, errGnarkCommitment, tc.name)
+	}
+	// two commitment keys with different G
+	g, gSigmaNeg := key[len(key)-2*g2Size:len(key)-g2Size], key[len(key)-g2Size:]
+	bad := append([]byte(nil), key[:data]...)
+	bad = append(bad, 0, 0, 0, 2, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 2)
+	bad = append(append(append(append(bad, g...), gSigmaNeg...), gSigmaNeg...), gSigmaNeg...)
+	_, err := ReadGnarkVerifyingKey(suite, bytes.NewReader(bad))
+	require.ErrorIs(t, err, errGnarkCommitment)
+}
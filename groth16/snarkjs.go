@@ -0,0 +1,171 @@
+package groth16
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/drand/kyber"
+	bls "github.com/drand/kyber-bls12381"
+	"github.com/drand/kyber/group/mod"
+	"github.com/drand/kyber/pairing"
+)
+
+// The JSON files of snarkjs hold the points in projective coordinates as decimal strings, with z = 1, or
+// z = 0 for the point at infinity, the elements of Fp2 being written c0, c1.
+
+// fpSize is the size of the big-endian coordinates of the uncompressed encodings.
+const fpSize = 48
+
+var errSnarkJS = errors.New("groth16: invalid snarkjs file")
+
+// snarkjsKey is the verification_key.json of snarkjs.
+type snarkjsKey struct {
+	Protocol string     `json:"protocol"`
+	Curve    string     `json:"curve"`
+	NPublic  int        `json:"nPublic"`
+	Alpha    []string   `json:"vk_alpha_1"`
+	Beta     [][]string `json:"vk_beta_2"`
+	Gamma    [][]string `json:"vk_gamma_2"`
+	Delta    [][]string `json:"vk_delta_2"`
+	IC       [][]string `json:"IC"`
+}
+
+// snarkjsProof is the proof.json of snarkjs.
+type snarkjsProof struct {
+	Protocol string     `json:"protocol"`
+	Curve    string     `json:"curve"`
+	A        []string   `json:"pi_a"`
+	B        [][]string `json:"pi_b"`
+	C        []string   `json:"pi_c"`
+}
+
+// ReadSnarkJSVerifyingKey reads the verification_key.json exported by snarkjs for a circuit on BLS12-381.
+func ReadSnarkJSVerifyingKey(suite pairing.Suite, r io.Reader) (*VerifyingKey, error) {
+	var key snarkjsKey
+	if err := json.NewDecoder(r).Decode(&key); err != nil {
+		return nil, fmt.Errorf("%w: %v", errSnarkJS, err)
+	}
+	if err := snarkjsCheck(key.Protocol, key.Curve); err != nil {
+		return nil, err
+	}
+	if len(key.IC) != key.NPublic+1 {
+		return nil, fmt.Errorf("%w: %d IC points for %d public inputs", errSnarkJS, len(key.IC), key.NPublic)
+	}
+	alpha, err := snarkjsG1(suite, key.Alpha)
+	if err != nil {
+		return nil, err
+	}
+	var g2 [3]kyber.Point
+	for i, p := range [][][]string{key.Beta, key.Gamma, key.Delta} {
+		if g2[i], err = snarkjsG2(suite, p); err != nil {
+			return nil, err
+		}
+	}
+	ic := make([]kyber.Point, len(key.IC))
+	for i, p := range key.IC {
+		if ic[i], err = snarkjsG1(suite, p); err != nil {
+			return nil, err
+		}
+	}
+	return NewVerifyingKey(suite, alpha, g2[0], g2[1], g2[2], ic)
+}
+
+// ReadSnarkJSProof reads the proof.json generated by snarkjs for a circuit on BLS12-381.
+func ReadSnarkJSProof(suite pairing.Suite, r io.Reader) (*Proof, error) {
+	var proof snarkjsProof
+	if err := json.NewDecoder(r).Decode(&proof); err != nil {
+		return nil, fmt.Errorf("%w: %v", errSnarkJS, err)
+	}
+	if err := snarkjsCheck(proof.Protocol, proof.Curve); err != nil {
+		return nil, err
+	}
+	a, err := snarkjsG1(suite, proof.A)
+	if err != nil {
+		return nil, err
+	}
+	b, err := snarkjsG2(suite, proof.B)
+	if err != nil {
+		return nil, err
+	}
+	c, err := snarkjsG1(suite, proof.C)
+	if err != nil {
+		return nil, err
+	}
+	return &Proof{A: a, B: b, C: c}, nil
+}
+
+// ReadSnarkJSPublic reads the public inputs of the public.json generated by snarkjs, an array of decimal
+// strings.
+func ReadSnarkJSPublic(suite pairing.Suite, r io.Reader) ([]kyber.Scalar, error) {
+	var public []string
+	if err := json.NewDecoder(r).Decode(&public); err != nil {
+		return nil, fmt.Errorf("%w: %v", errSnarkJS, err)
+	}
+	xs := make([]kyber.Scalar, len(public))
+	for i, s := range public {
+		x := suite.G1().Scalar()
+		n, ok := new(big.Int).SetString(s, 10)
+		if !ok || n.Sign() < 0 || n.Cmp(x.(*mod.Int).M) >= 0 {
+			return nil, fmt.Errorf("%w: public input %q", errSnarkJS, s)
+		}
+		xs[i] = x.SetBytes(n.Bytes())
+	}
+	return xs, nil
+}
+
+func snarkjsCheck(protocol, curve string) error {
+	if protocol != "groth16" {
+		return fmt.Errorf("%w: protocol %q", errSnarkJS, protocol)
+	}
+	if curve != "bls12381" {
+		return fmt.Errorf("%w: curve %q", errSnarkJS, curve)
+	}
+	return nil
+}
+
+// snarkjsG1 decodes the point of G1 [x, y, z].
+func snarkjsG1(suite pairing.Suite, coords []string) (kyber.Point, error) {
+	if len(coords) != 3 {
+		return nil, fmt.Errorf("%w: G1 point of %d coordinates", errSnarkJS, len(coords))
+	}
+	return snarkjsPoint(suite.G1().Point(), coords[2] == "0", coords[2] == "1", coords[:2])
+}
+
+// snarkjsG2 decodes the point of G2 [[x0, x1], [y0, y1], [z0, z1]].
+func snarkjsG2(suite pairing.Suite, coords [][]string) (kyber.Point, error) {
+	if len(coords) != 3 || len(coords[0]) != 2 || len(coords[1]) != 2 || len(coords[2]) != 2 {
+		return nil, fmt.Errorf("%w: malformed G2 point", errSnarkJS)
+	}
+	infinity := coords[2][0] == "0" && coords[2][1] == "0"
+	one := coords[2][0] == "1" && coords[2][1] == "0"
+	// the encoding of Fp2 starts with c1
+	xy := []string{coords[0][1], coords[0][0], coords[1][1], coords[1][0]}
+	return snarkjsPoint(suite.G2().Point(), infinity, one, xy)
+}
+
+// snarkjsPoint sets p from its affine coordinates, or to the point at infinity, checking that it is in the
+// subgroup.
+func snarkjsPoint(p kyber.Point, infinity, one bool, coords []string) (kyber.Point, error) {
+	switch {
+	case infinity:
+		return p.Null(), nil
+	case !one:
+		return nil, fmt.Errorf("%w: point not in affine coordinates", errSnarkJS)
+	}
+	buf := make([]byte, len(coords)*fpSize)
+	for i, s := range coords {
+		n, ok := new(big.Int).SetString(s, 10)
+		// at most 381 bits leave the flags of the encoding unset, and the decoding checks that they are reduced
+		if !ok || n.Sign() < 0 || n.BitLen() > 381 {
+			return nil, fmt.Errorf("%w: coordinate %q", errSnarkJS, s)
+		}
+		n.FillBytes(buf[i*fpSize : (i+1)*fpSize])
+	}
+	if err := bls.ProfileZCashUncompressed.Unmarshal(p, buf); err != nil {
+		return nil, fmt.Errorf("%w: %v", errSnarkJS, err)
+	}
+	return p, nil
+}
@@ -0,0 +1,122 @@
+// Command gnark writes the verifying keys, proofs and public inputs of two circuits on BLS12-381, proved
+// with the Groth16 backend of gnark: cubic, without commitments, and committed, which uses the Commit API.
+//
+//	go run . <directory>
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+)
+
+// cubic proves the knowledge of x such that x³ + x + 5 = y and x + y = z.
+type cubic struct {
+	X    frontend.Variable
+	Y, Z frontend.Variable `gnark:",public"`
+}
+
+func (c *cubic) Define(api frontend.API) error {
+	x3 := api.Mul(c.X, c.X, c.X)
+	api.AssertIsEqual(c.Y, api.Add(x3, c.X, 5))
+	api.AssertIsEqual(c.Z, api.Add(c.X, c.Y))
+	return nil
+}
+
+// committed is cubic with a commitment to x and y.
+type committed struct {
+	cubic
+}
+
+func (c *committed) Define(api frontend.API) error {
+	if err := c.cubic.Define(api); err != nil {
+		return err
+	}
+	cmt, err := api.(frontend.Committer).Commit(c.X, c.Y)
+	if err != nil {
+		return err
+	}
+	api.AssertIsDifferent(cmt, 0)
+	return nil
+}
+
+func main() {
+	if len(os.Args) != 2 {
+		log.Fatal("usage: go run . <directory>")
+	}
+	dir := os.Args[1]
+	// x = 3, y = 35, z = 38
+	assignment := cubic{X: 3, Y: 35, Z: 38}
+	if err := write(dir, "cubic", &cubic{}, &assignment); err != nil {
+		log.Fatal(err)
+	}
+	if err := write(dir, "committed", &committed{}, &committed{assignment}); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func write(dir, name string, circuit, assignment frontend.Circuit) error {
+	cs, err := frontend.Compile(ecc.BLS12_381.ScalarField(), r1cs.NewBuilder, circuit)
+	if err != nil {
+		return err
+	}
+	pk, vk, err := groth16.Setup(cs)
+	if err != nil {
+		return err
+	}
+	w, err := frontend.NewWitness(assignment, ecc.BLS12_381.ScalarField())
+	if err != nil {
+		return err
+	}
+	proof, err := groth16.Prove(cs, pk, w)
+	if err != nil {
+		return err
+	}
+	public, err := w.Public()
+	if err != nil {
+		return err
+	}
+	if err := groth16.Verify(proof, vk, public); err != nil {
+		return err
+	}
+	files := map[string]io.WriterTo{
+		name + "_vk.bin":        vk,
+		name + "_proof.bin":     proof,
+		name + "_vk_raw.bin":    writerTo(vk.WriteRawTo),
+		name + "_proof_raw.bin": writerTo(proof.WriteRawTo),
+	}
+	for file, wt := range files {
+		f, err := os.Create(filepath.Join(dir, file))
+		if err != nil {
+			return err
+		}
+		if _, err := wt.WriteTo(f); err != nil {
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+	}
+	// the public inputs as in the public.json of snarkjs
+	var inputs []string
+	for _, x := range public.Vector().(fr.Vector) {
+		inputs = append(inputs, x.String())
+	}
+	buf, err := json.Marshal(inputs)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, name+"_public.json"), append(buf, '\n'), 0o644)
+}
+
+type writerTo func(io.Writer) (int64, error)
+
+func (f writerTo) WriteTo(w io.Writer) (int64, error) { return f(w) }
@@ -0,0 +1,154 @@
+package bls
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/drand/kyber"
+	"github.com/drand/kyber/group/mod"
+)
+
+const (
+	// hashToFieldL is the number of bytes L expanded per field element, as fixed by RFC 9380 for BLS12-381.
+	hashToFieldL = 64
+	// hashToScalarL is the number of bytes L expanded per scalar, ceil((ceil(log2(r)) + k) / 8) with k = 128.
+	hashToScalarL = 48
+)
+
+// SuiteIDG1 returns the RFC 9380 hash to curve suite ID for G1 when using the given expander,
+// e.g. "BLS12381G1_XOF:SHAKE256_SSWU_RO_". A nil expander is the default expand_message_xmd with SHA-256.
+func SuiteIDG1(exp Expander) string {
+	return "BLS12381G1_" + expanderOrDefault(exp).String() + "_SSWU_RO_"
+}
+
+// SuiteIDG2 returns the RFC 9380 hash to curve suite ID for G2 when using the given expander,
+// e.g. "BLS12381G2_XOF:SHAKE256_SSWU_RO_". A nil expander is the default expand_message_xmd with SHA-256.
+func SuiteIDG2(exp Expander) string {
+	return "BLS12381G2_" + expanderOrDefault(exp).String() + "_SSWU_RO_"
+}
+
+// DomainG1WithExpander returns the default BLS signature DST on G1 for the suite using the given expander.
+// For the default expander this is the same as DefaultDomainG1.
+func DomainG1WithExpander(exp Expander) []byte {
+	return []byte("BLS_SIG_" + SuiteIDG1(exp) + "NUL_")
+}
+
+// DomainG2WithExpander returns the default BLS signature DST on G2 for the suite using the given expander.
+// For the default expander this is the same as DefaultDomainG2.
+func DomainG2WithExpander(exp Expander) []byte {
+	return []byte("BLS_SIG_" + SuiteIDG2(exp) + "NUL_")
+}
+
+func expanderOrDefault(exp Expander) Expander {
+	if exp == nil {
+		return ExpanderXMDSHA256
+	}
+	return exp
+}
+
+// normalizeExpander maps the default expander to nil, so that points hashed with it keep using the
+// kilic implementation and compare equal to points created without an explicit expander.
+func normalizeExpander(exp Expander) Expander {
+	if exp == ExpanderXMDSHA256 {
+		return nil
+	}
+	return exp
+}
+
+// HashToFieldFp implements hash_to_field from RFC 9380 section 5.2 for Fp, returning count elements
+// reduced modulo p. A nil expander is the default expand_message_xmd with SHA-256.
+func HashToFieldFp(msg, dst []byte, count int, exp Expander) ([]*big.Int, error) {
+	return hashToField(msg, dst, count, hashToFieldL, fieldModulus, exp)
+}
+
+// HashToFieldFp2 implements hash_to_field from RFC 9380 section 5.2 for Fp2, returning count elements.
+// A nil expander is the default expand_message_xmd with SHA-256.
+func HashToFieldFp2(msg, dst []byte, count int, exp Expander) ([]*Fp2, error) {
+	u, err := hashToField(msg, dst, 2*count, hashToFieldL, fieldModulus, exp)
+	if err != nil {
+		return nil, err
+	}
+	els := make([]*Fp2, count)
+	for i := range els {
+		els[i] = &Fp2{C0: u[2*i], C1: u[2*i+1]}
+	}
+	return els, nil
+}
+
+// HashToScalar derives a scalar of the BLS12-381 groups from msg, using hash_to_field with
+// expand_message_xmd and SHA-256 under the given DST. The result is compatible with NewKyberScalar.
+func HashToScalar(msg, dst []byte) kyber.Scalar {
+	return HashToScalarWithExpander(msg, dst, nil)
+}
+
+// HashToScalarWithExpander is like HashToScalar, using the given expander instead of the default one.
+func HashToScalarWithExpander(msg, dst []byte, exp Expander) kyber.Scalar {
+	// the only possible error is an oversized output, which cannot happen for a single scalar
+	u, _ := hashToField(msg, dst, 1, hashToScalarL, curveOrder, exp)
+	return mod.NewInt(u[0], curveOrder)
+}
+
+// HashToGroup returns msg hashed to the curve under dst with the default expander, as a point of g, G1 or G2,
+// with the domain and the backend of its points: the point is the hash of a point created with dst, but it
+// can be mixed with the points of g under WithStrictDomains. It returns an error wrapping ErrWrongType for
+// the other groups.
+func HashToGroup(g kyber.Group, msg, dst []byte) (kyber.Point, error) {
+	switch p := g.Point().(type) {
+	case *KyberG1:
+		h := &KyberG1{backend: p.setIfEmpty().backend, dst: dst}
+		p.p = h.Hash(msg).(*KyberG1).p
+		return p, nil
+	case *KyberG2:
+		h := &KyberG2{backend: p.setIfEmpty().backend, dst: dst}
+		p.p = h.Hash(msg).(*KyberG2).p
+		return p, nil
+	default:
+		return nil, fmt.Errorf("%w: %T is not a G1 or G2 point", ErrWrongType, p)
+	}
+}
+
+func hashToField(msg, dst []byte, count, l int, modulus *big.Int, exp Expander) ([]*big.Int, error) {
+	uniform, err := expanderOrDefault(exp).ExpandMessage(msg, dst, count*l)
+	if err != nil {
+		return nil, err
+	}
+	els := make([]*big.Int, count)
+	for i := range els {
+		e := new(big.Int).SetBytes(uniform[i*l : (i+1)*l])
+		els[i] = e.Mod(e, modulus)
+	}
+	return els, nil
+}
+
+// hashToCurveG1 implements the BLS12381G1 random oracle encoding of RFC 9380 with an arbitrary expander.
+// Since the isogeny and the cofactor clearing are group homomorphisms, summing the fully mapped points
+// gives the same result as clearing the cofactor of the sum.
+func hashToCurveG1(b Backend, msg, dst []byte, exp Expander) (point, error) {
+	u, err := HashToFieldFp(msg, dst, 2, exp)
+	if err != nil {
+		return nil, err
+	}
+	return hashToCurve(b.g1(), fpToBytes(u[0]), fpToBytes(u[1]))
+}
+
+// hashToCurveG2 implements the BLS12381G2 random oracle encoding of RFC 9380 with an arbitrary expander.
+func hashToCurveG2(b Backend, msg, dst []byte, exp Expander) (point, error) {
+	u, err := HashToFieldFp2(msg, dst, 2, exp)
+	if err != nil {
+		return nil, err
+	}
+	return hashToCurve(b.g2(), u[0].Bytes(), u[1].Bytes())
+}
+
+func hashToCurve(c curve, u0, u1 []byte) (point, error) {
+	q0, err := c.mapToCurve(u0)
+	if err != nil {
+		return nil, err
+	}
+	q1, err := c.mapToCurve(u1)
+	if err != nil {
+		return nil, err
+	}
+	q0.add(q0, q1)
+	return q0, nil
+}
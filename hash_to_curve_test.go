@@ -0,0 +1,85 @@
+package bls
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/drand/kyber"
+	"github.com/drand/kyber/group/mod"
+	"github.com/stretchr/testify/require"
+)
+
+// hash_to_field outputs from the RFC 9380 appendix J.9.1 and J.10.1 test vectors
+func TestHashToField(t *testing.T) {
+	u, err := HashToFieldFp([]byte("abc"), []byte("QUUX-V01-CS02-with-BLS12381G1_XMD:SHA-256_SSWU_RO_"), 2, nil)
+	require.NoError(t, err)
+	require.Equal(t, "d921c33f2bad966478a03ca35d05719bdf92d347557ea166e5bba579eea9b83e9afa5c088573c2281410369fbd32951", u[0].Text(16))
+	require.Equal(t, "3574a00b109ada2f26a37a91f9d1e740dffd8d69ec0c35e1e9f4652c7dba61123e9dd2e76c655d956e2b3462611139", u[1].Text(16))
+
+	u2, err := HashToFieldFp2(nil, []byte("QUUX-V01-CS02-with-BLS12381G2_XMD:SHA-256_SSWU_RO_"), 2, ExpanderXMDSHA256)
+	require.NoError(t, err)
+	require.Equal(t, "3dbc2cce174e91ba93cbb08f26b917f98194a2ea08d1cce75b2b9cc9f21689d80bd79b594a613d0a68eb807dfdc1cf8", u2[0].C0.Text(16))
+	require.Equal(t, "5a2acec64114845711a54199ea339abd125ba38253b70a92c876df10598bd1986b739cad67961eb94f7076511b3b39a", u2[0].C1.Text(16))
+	require.Equal(t, "2f99798e8a5acdeed60d7e18e9120521ba1f47ec090984662846bc825de191b5b7641148c0dbc237726a334473eee94", u2[1].C0.Text(16))
+	require.Equal(t, "145a81e418d4010cc027a68f14391b30074e89e60ee7a22f87217b2f6eb0c4b94c9115b436e6fa4607e95a98de30a435", u2[1].C1.Text(16))
+}
+
+func TestHashToScalar(t *testing.T) {
+	dst := []byte("TEST-HASH-TO-SCALAR")
+	s1 := HashToScalar([]byte("msg"), dst)
+	require.True(t, s1.Equal(HashToScalar([]byte("msg"), dst)))
+	require.False(t, s1.Equal(HashToScalar([]byte("msg"), []byte("OTHER-DST"))))
+	require.False(t, s1.Equal(HashToScalarWithExpander([]byte("msg"), dst, ExpanderXOFSHAKE256)))
+	require.True(t, s1.(*mod.Int).V.Cmp(curveOrder) < 0)
+
+	// the scalar must interoperate with the ones of the groups
+	s2 := NewKyberScalar().Add(s1, NewKyberScalar().One())
+	p1 := NewGroupG1().Point().Mul(s2, nil)
+	p2 := NewGroupG1().Point().Mul(s1, nil)
+	require.True(t, p1.Equal(p2.Add(p2, NewGroupG1().Point().Base())))
+}
+
+// Fp2.Bytes must encode the residues of coefficients which are negative or not reduced, e.g. a 49 byte value.
+func TestHashToGroup(t *testing.T) {
+	msg, dst := []byte("msg"), []byte("some DST")
+	for _, b := range backends {
+		suite := NewBLS12381Suite(WithBackend(b), WithStrictDomains())
+		for _, tc := range []struct {
+			g    kyber.Group
+			want kyber.Point
+		}{
+			{suite.G1(), NullKyberG1(dst...).Hash(msg)},
+			{suite.G2(), NullKyberG2(dst...).Hash(msg)},
+		} {
+			h, err := HashToGroup(tc.g, msg, dst)
+			require.NoError(t, err)
+			buf, err := h.MarshalBinary()
+			require.NoError(t, err)
+			want, err := tc.want.MarshalBinary()
+			require.NoError(t, err)
+			require.Equal(t, want, buf)
+			// the point has the domain of g
+			require.NotPanics(t, func() { tc.g.Point().Add(h, tc.g.Point().Base()) })
+		}
+		_, err := HashToGroup(suite.GT(), msg, dst)
+		require.ErrorIs(t, err, ErrWrongType)
+	}
+}
+
+func TestFp2Bytes(t *testing.T) {
+	one, two := big.NewInt(1), big.NewInt(2)
+	want := (&Fp2{C0: one, C1: two}).Bytes()
+	pMinusOne := new(big.Int).Sub(fieldModulus, one)
+	huge := new(big.Int).Lsh(fieldModulus, 8)
+	huge.Add(huge, two)
+	require.Equal(t, want, (&Fp2{C0: new(big.Int).Add(fieldModulus, one), C1: huge}).Bytes())
+	require.Equal(t, (&Fp2{C0: pMinusOne, C1: new(big.Int).Sub(fieldModulus, two)}).Bytes(),
+		(&Fp2{C0: big.NewInt(-1), C1: big.NewInt(-2)}).Bytes())
+
+	// the map to the curve of an unreduced element is the one of its residue
+	p, err := MapToCurveG2(&Fp2{C0: new(big.Int).Add(fieldModulus, one), C1: huge})
+	require.NoError(t, err)
+	q, err := MapToCurveG2(&Fp2{C0: one, C1: two})
+	require.NoError(t, err)
+	require.True(t, p.Equal(q))
+}
@@ -8,7 +8,6 @@ import (
 
 	"github.com/drand/kyber"
 	"github.com/drand/kyber/group/mod"
-	bls12381 "github.com/kilic/bls12-381"
 )
 
 // domainG1 is the DST used for hash to curve on G1, this is the default from the RFC.
@@ -20,24 +19,65 @@ func DefaultDomainG1() []byte {
 
 // KyberG1 is a kyber.Point holding a G1 point on BLS12-381 curve
 type KyberG1 struct {
-	p *bls12381.PointG1
+	p point
+	// backend holding p, set by UnmarshalBinary for zero values
+	backend Backend
 	// domain separation tag. We treat a 0 len dst as the default value as per the RFC "Tags MUST have nonzero length"
 	dst []byte
+	// expander used by Hash. A nil expander is the RFC default expand_message_xmd with SHA-256.
+	exp Expander
+	// strict makes the arithmetic panic when mixing points of different domains, see WithStrictDomains.
+	strict bool
+	// oversize makes Hash follow RFC 9380 for tags longer than 255 bytes, see WithOversizeDomains.
+	oversize bool
+	// outside is set when p may not be in G1, e.g. for the points of MapToCurveG1, FromAffine and Embed, so
+	// Mul does not use the GLV method of the backends, which is only correct in G1.
+	outside bool
 
 	kyber.Point
 	kyber.HashablePoint
 }
 
 func NullKyberG1(dst ...byte) *KyberG1 {
-	var p bls12381.PointG1
-	return newKyberG1(&p, dst)
+	return newKyberG1(defaultBackend.g1().zero(), dst, nil)
 }
-func newKyberG1(p *bls12381.PointG1, dst []byte) *KyberG1 {
+
+// NullKyberG1WithExpander returns a G1 point hashing to the curve with the given expander and DST.
+// A nil or empty DST is the default BLS signature DST for the expander, see DomainG1WithExpander.
+func NullKyberG1WithExpander(exp Expander, dst ...byte) *KyberG1 {
+	return newKyberG1(defaultBackend.g1().zero(), dst, exp)
+}
+func newKyberG1(p point, dst []byte, exp Expander) *KyberG1 {
+	exp = normalizeExpander(exp)
 	domain := dst
-	if bytes.Equal(dst, domainG1) {
+	if bytes.Equal(dst, DomainG1WithExpander(exp)) {
 		domain = nil
 	}
-	return &KyberG1{p: p, dst: domain}
+	return &KyberG1{p: p, backend: p.backend(), dst: domain, exp: exp}
+}
+
+// pointIn returns the point of k in the representation of the backend b, the point at infinity for a zero
+// value.
+func (k *KyberG1) pointIn(b Backend) point {
+	if k.p == nil {
+		return b.g1().zero()
+	}
+	return convertPoint(b, b.g1(), k.p)
+}
+
+// in returns the point of q in the representation of the backend of k.
+func (k *KyberG1) in(q kyber.Point) point {
+	return q.(*KyberG1).pointIn(backendOrDefault(k.backend))
+}
+
+// setIfEmpty sets k to the point at infinity if it does not hold a point, e.g. for a zero value, so that
+// the methods setting k can be called on zero values.
+func (k *KyberG1) setIfEmpty() *KyberG1 {
+	if k.p == nil {
+		k.backend = backendOrDefault(k.backend)
+		k.p = k.backend.g1().zero()
+	}
+	return k
 }
 
 func (k *KyberG1) Equal(k2 kyber.Point) bool {
@@ -45,15 +85,39 @@ func (k *KyberG1) Equal(k2 kyber.Point) bool {
 	if !ok {
 		return false
 	}
-	return bls12381.NewG1().Equal(k.p, k2g1.p) && bytes.Equal(k.dst, k2g1.dst)
+	return k.pointIn(backendOrDefault(k.backend)).equal(k.in(k2g1)) && bytes.Equal(k.dst, k2g1.dst) && k.exp == k2g1.exp
+}
+
+// withDomain returns a point holding p, with the domain of k.
+func (k *KyberG1) withDomain(p point) *KyberG1 {
+	q := newKyberG1(p, k.dst, k.exp)
+	q.strict, q.oversize = k.strict, k.oversize
+	return q
+}
+
+// mix panics with ErrDomainMismatch if k or one of ps is strict, and the points of ps do not all have the
+// domain of k.
+func (k *KyberG1) mix(ps ...*KyberG1) {
+	strict := k.strict
+	for _, p := range ps {
+		strict = strict || p.strict
+	}
+	if !strict {
+		return
+	}
+	for _, p := range ps {
+		if err := checkDomain(k.domain(), p.domain(), k.exp, p.exp); err != nil {
+			panic(err)
+		}
+	}
 }
 
 func (k *KyberG1) Null() kyber.Point {
-	return newKyberG1(bls12381.NewG1().Zero(), k.dst)
+	return k.withDomain(backendOrDefault(k.backend).g1().zero())
 }
 
 func (k *KyberG1) Base() kyber.Point {
-	return newKyberG1(bls12381.NewG1().One(), k.dst)
+	return k.withDomain(backendOrDefault(k.backend).g1().generator())
 }
 
 func (k *KyberG1) Pick(rand cipher.Stream) kyber.Point {
@@ -62,70 +126,127 @@ func (k *KyberG1) Pick(rand cipher.Stream) kyber.Point {
 	return k.Hash(dst[:])
 }
 
+// Set sets k to q, along with its domain. k stays strict if it was.
 func (k *KyberG1) Set(q kyber.Point) kyber.Point {
-	k.p.Set(q.(*KyberG1).p)
+	qq := q.(*KyberG1)
+	k.setIfEmpty().p.set(k.in(qq))
+	k.dst, k.exp, k.oversize = qq.dst, qq.exp, qq.oversize
+	k.strict = k.strict || qq.strict
+	k.outside = qq.outside
 	return k
 }
 
+// Clone returns a copy of k, along with its domain.
 func (k *KyberG1) Clone() kyber.Point {
-	var p bls12381.PointG1
-	p.Set(k.p)
-	return newKyberG1(&p, k.dst)
+	q := k.withDomain(k.pointIn(backendOrDefault(k.backend)).clone())
+	q.outside = k.outside
+	return q
 }
 
+// EmbedLen returns the number of bytes that Embed writes in a point.
 func (k *KyberG1) EmbedLen() int {
-	panic("bls12-381: unsupported operation")
+	return embedLen(1)
 }
 
+// Embed sets k to a random point of E1 whose x-coordinate holds the first EmbedLen bytes of data. The point is
+// not in G1, except with negligible probability, so UnmarshalBinary rejects its encoding: it can be sent with
+// Affine and FromAffine. Mul and MultiExp handle it, as the points of MapToCurveG1.
 func (k *KyberG1) Embed(data []byte, rand cipher.Stream) kyber.Point {
-	panic("bls12-381: unsupported operation")
+	k.backend = backendOrDefault(k.backend)
+	p, err := k.backend.g1().fromUncompressed(embedG1(data, rand))
+	if err != nil {
+		panic(err)
+	}
+	k.p, k.outside = p, !p.inSubgroup()
+	return k
 }
 
+// Data returns the data embedded in k by Embed, or an error if k does not hold embedded data.
 func (k *KyberG1) Data() ([]byte, error) {
-	panic("bls12-381: unsupported operation")
+	return embeddedData(k.pointIn(backendOrDefault(k.backend)).uncompressed(), 1)
 }
 
 func (k *KyberG1) Add(a, b kyber.Point) kyber.Point {
 	aa := a.(*KyberG1)
 	bb := b.(*KyberG1)
-	bls12381.NewG1().Add(k.p, aa.p, bb.p)
+	k.mix(aa, bb)
+	k.setIfEmpty().p.add(k.in(aa), k.in(bb))
+	k.outside = aa.outside || bb.outside
 	return k
 }
 
 func (k *KyberG1) Sub(a, b kyber.Point) kyber.Point {
 	aa := a.(*KyberG1)
 	bb := b.(*KyberG1)
-	bls12381.NewG1().Sub(k.p, aa.p, bb.p)
+	k.mix(aa, bb)
+	k.setIfEmpty().p.sub(k.in(aa), k.in(bb))
+	k.outside = aa.outside || bb.outside
 	return k
 }
 
 func (k *KyberG1) Neg(a kyber.Point) kyber.Point {
 	aa := a.(*KyberG1)
-	bls12381.NewG1().Neg(k.p, aa.p)
+	k.mix(aa)
+	k.setIfEmpty().p.neg(k.in(aa))
+	k.outside = aa.outside
 	return k
 }
 
 func (k *KyberG1) Mul(s kyber.Scalar, q kyber.Point) kyber.Point {
 	if q == nil {
-		q = NullKyberG1(k.dst...).Base()
+		q = k.Base()
+	}
+	qq := q.(*KyberG1)
+	k.mix(qq)
+	k.setIfEmpty()
+	if qq.outside {
+		k.p.mulAny(k.in(qq), &s.(*mod.Int).V)
+	} else {
+		k.p.mul(k.in(qq), &s.(*mod.Int).V)
 	}
-	bls12381.NewG1().MulScalarBig(k.p, q.(*KyberG1).p, &s.(*mod.Int).V)
+	k.outside = qq.outside
 	return k
 }
 
-// MarshalBinary returns a compressed point, without any domain separation tag information
+// MarshalBinary returns a compressed point, without any domain separation tag information,
+// see MarshalWithDomain to keep it.
 func (k *KyberG1) MarshalBinary() ([]byte, error) {
-	// we need to clone the point because of https://github.com/kilic/bls12-381/issues/37
-	// in order to avoid risks of race conditions.
-	t := new(bls12381.PointG1).Set(k.p)
-	return bls12381.NewG1().ToCompressed(t), nil
+	return k.pointIn(backendOrDefault(k.backend)).compressed(), nil
 }
 
-// UnmarshalBinary populates the point from a compressed point representation.
+// UnmarshalBinary populates the point from a compressed point representation. The point is left
+// unchanged if the encoding is rejected, with one of the decoding errors such as ErrNotInSubgroup.
 func (k *KyberG1) UnmarshalBinary(buff []byte) error {
-	var err error
-	k.p, err = bls12381.NewG1().FromCompressed(buff)
-	return err
+	// zero values, e.g. allocated by encoding/json, use the default backend
+	k.backend = backendOrDefault(k.backend)
+	p, err := decodeCompressed(k.backend.g1(), buff, k.MarshalSize())
+	if err != nil {
+		return err
+	}
+	k.p, k.outside = p, false
+	return nil
+}
+
+// MarshalWithDomain returns the compressed point along with the DST and the expander used by Hash, so that
+// UnmarshalWithDomain restores a point Equal to k.
+func (k *KyberG1) MarshalWithDomain() ([]byte, error) {
+	buf, _ := k.MarshalBinary()
+	return marshalDomain(k.domain(), k.exp, buf)
+}
+
+// UnmarshalWithDomain populates the point and its domain from the encoding written by MarshalWithDomain.
+// The expander must be one of the expanders of this package, or the expander of k.
+func (k *KyberG1) UnmarshalWithDomain(buf []byte) error {
+	dst, exp, point, err := unmarshalDomain(buf, k.exp)
+	if err != nil {
+		return err
+	}
+	if err := k.UnmarshalBinary(point); err != nil {
+		return err
+	}
+	q := newKyberG1(k.p, dst, exp)
+	k.dst, k.exp = q.dst, q.exp
+	return nil
 }
 
 // MarshalTo writes a compressed point to the Writer, without any domain separation tag information
@@ -156,17 +277,45 @@ func (k *KyberG1) String() string {
 	return "bls12-381.G1: " + hex.EncodeToString(b)
 }
 
-func (k *KyberG1) Hash(m []byte) kyber.Point {
-	domain := domainG1
+// domain returns the DST used by Hash.
+func (k *KyberG1) domain() []byte {
 	// We treat a 0 len dst as the default value as per the RFC "Tags MUST have nonzero length"
 	if len(k.dst) != 0 {
-		domain = k.dst
+		return k.dst
 	}
-	p, _ := bls12381.NewG1().HashToCurve(m, domain)
-	k.p = p
+	return DomainG1WithExpander(k.exp)
+}
+
+func (k *KyberG1) Hash(m []byte) kyber.Point {
+	k.setIfEmpty()
+	domain := k.domain()
+	switch {
+	case k.exp != nil || len(domain) > 255 && k.oversize:
+		// backends do not implement the other expanders, nor the hashing of tags longer than 255 bytes
+		k.p, _ = hashToCurveG1(k.backend, m, domain, k.exp)
+	case len(domain) > 255:
+		// as by previous releases, see WithOversizeDomains
+		k.p, _ = hashToCurveG1(k.backend, m, domain, expanderXMDTruncated)
+	default:
+		k.p, _ = k.backend.g1().hashToCurve(m, domain)
+	}
+	k.outside = false
+	return k
+}
+
+// ClearCofactor sets k to the image of q under clear_cofactor from RFC 9380 section 7, which maps any
+// point of E1 to G1, e.g. the result of MapToCurveG1.
+func (k *KyberG1) ClearCofactor(q kyber.Point) kyber.Point {
+	qq := q.(*KyberG1)
+	k.mix(qq)
+	k.setIfEmpty().p.set(k.in(qq))
+	k.p.clearCofactor()
+	k.outside = false
 	return k
 }
 
+// IsInCorrectGroup returns whether k is a point of G1. It returns false when k does not hold a point,
+// e.g. a zero value.
 func (k *KyberG1) IsInCorrectGroup() bool {
-	return bls12381.NewG1().InCorrectSubgroup(k.p)
+	return k.p != nil && k.p.inSubgroup()
 }
@@ -8,7 +8,6 @@ import (
 
 	"github.com/drand/kyber"
 	"github.com/drand/kyber/group/mod"
-	bls12381 "github.com/kilic/bls12-381"
 )
 
 // domainG2 is the DST used for hash to curve on G2, this is the default from the RFC.
@@ -21,22 +20,63 @@ func DefaultDomainG2() []byte {
 
 // KyberG2 is a kyber.Point holding a G2 point on BLS12-381 curve
 type KyberG2 struct {
-	p *bls12381.PointG2
+	p point
+	// backend holding p, set by UnmarshalBinary for zero values
+	backend Backend
 	// domain separation tag. We treat a 0 len dst as the default value as per the RFC "Tags MUST have nonzero length"
 	dst []byte
+	// expander used by Hash. A nil expander is the RFC default expand_message_xmd with SHA-256.
+	exp Expander
+	// strict makes the arithmetic panic when mixing points of different domains, see WithStrictDomains.
+	strict bool
+	// oversize makes Hash follow RFC 9380 for tags longer than 255 bytes, see WithOversizeDomains.
+	oversize bool
+	// outside is set when p may not be in G2, e.g. for the points of MapToCurveG2, FromAffine and Embed, so
+	// Mul does not use the GLV method of the backends, which is only correct in G2.
+	outside bool
 }
 
 func NullKyberG2(dst ...byte) *KyberG2 {
-	var p bls12381.PointG2
-	return newKyberG2(&p, dst)
+	return newKyberG2(defaultBackend.g2().zero(), dst, nil)
 }
 
-func newKyberG2(p *bls12381.PointG2, dst []byte) *KyberG2 {
+// NullKyberG2WithExpander returns a G2 point hashing to the curve with the given expander and DST.
+// A nil or empty DST is the default BLS signature DST for the expander, see DomainG2WithExpander.
+func NullKyberG2WithExpander(exp Expander, dst ...byte) *KyberG2 {
+	return newKyberG2(defaultBackend.g2().zero(), dst, exp)
+}
+
+func newKyberG2(p point, dst []byte, exp Expander) *KyberG2 {
+	exp = normalizeExpander(exp)
 	domain := dst
-	if bytes.Equal(dst, domainG2) {
+	if bytes.Equal(dst, DomainG2WithExpander(exp)) {
 		domain = nil
 	}
-	return &KyberG2{p: p, dst: domain}
+	return &KyberG2{p: p, backend: p.backend(), dst: domain, exp: exp}
+}
+
+// pointIn returns the point of k in the representation of the backend b, the point at infinity for a zero
+// value.
+func (k *KyberG2) pointIn(b Backend) point {
+	if k.p == nil {
+		return b.g2().zero()
+	}
+	return convertPoint(b, b.g2(), k.p)
+}
+
+// in returns the point of q in the representation of the backend of k.
+func (k *KyberG2) in(q kyber.Point) point {
+	return q.(*KyberG2).pointIn(backendOrDefault(k.backend))
+}
+
+// setIfEmpty sets k to the point at infinity if it does not hold a point, e.g. for a zero value, so that
+// the methods setting k can be called on zero values.
+func (k *KyberG2) setIfEmpty() *KyberG2 {
+	if k.p == nil {
+		k.backend = backendOrDefault(k.backend)
+		k.p = k.backend.g2().zero()
+	}
+	return k
 }
 
 func (k *KyberG2) Equal(k2 kyber.Point) bool {
@@ -44,15 +84,39 @@ func (k *KyberG2) Equal(k2 kyber.Point) bool {
 	if !ok {
 		return false
 	}
-	return bls12381.NewG2().Equal(k.p, k2g2.p) && bytes.Equal(k.dst, k2g2.dst)
+	return k.pointIn(backendOrDefault(k.backend)).equal(k.in(k2g2)) && bytes.Equal(k.dst, k2g2.dst) && k.exp == k2g2.exp
+}
+
+// withDomain returns a point holding p, with the domain of k.
+func (k *KyberG2) withDomain(p point) *KyberG2 {
+	q := newKyberG2(p, k.dst, k.exp)
+	q.strict, q.oversize = k.strict, k.oversize
+	return q
+}
+
+// mix panics with ErrDomainMismatch if k or one of ps is strict, and the points of ps do not all have the
+// domain of k.
+func (k *KyberG2) mix(ps ...*KyberG2) {
+	strict := k.strict
+	for _, p := range ps {
+		strict = strict || p.strict
+	}
+	if !strict {
+		return
+	}
+	for _, p := range ps {
+		if err := checkDomain(k.domain(), p.domain(), k.exp, p.exp); err != nil {
+			panic(err)
+		}
+	}
 }
 
 func (k *KyberG2) Null() kyber.Point {
-	return newKyberG2(bls12381.NewG2().Zero(), k.dst)
+	return k.withDomain(backendOrDefault(k.backend).g2().zero())
 }
 
 func (k *KyberG2) Base() kyber.Point {
-	return newKyberG2(bls12381.NewG2().One(), k.dst)
+	return k.withDomain(backendOrDefault(k.backend).g2().generator())
 }
 
 func (k *KyberG2) Pick(rand cipher.Stream) kyber.Point {
@@ -61,70 +125,127 @@ func (k *KyberG2) Pick(rand cipher.Stream) kyber.Point {
 	return k.Hash(dst[:])
 }
 
+// Set sets k to q, along with its domain. k stays strict if it was.
 func (k *KyberG2) Set(q kyber.Point) kyber.Point {
-	k.p.Set(q.(*KyberG2).p)
+	qq := q.(*KyberG2)
+	k.setIfEmpty().p.set(k.in(qq))
+	k.dst, k.exp, k.oversize = qq.dst, qq.exp, qq.oversize
+	k.strict = k.strict || qq.strict
+	k.outside = qq.outside
 	return k
 }
 
+// Clone returns a copy of k, along with its domain.
 func (k *KyberG2) Clone() kyber.Point {
-	var p bls12381.PointG2
-	p.Set(k.p)
-	return newKyberG2(&p, k.dst)
+	q := k.withDomain(k.pointIn(backendOrDefault(k.backend)).clone())
+	q.outside = k.outside
+	return q
 }
 
+// EmbedLen returns the number of bytes that Embed writes in a point.
 func (k *KyberG2) EmbedLen() int {
-	panic("bls12-381: unsupported operation")
+	return embedLen(2)
 }
 
+// Embed sets k to a random point of E2 whose x-coordinate holds the first EmbedLen bytes of data. The point is
+// not in G2, except with negligible probability, so UnmarshalBinary rejects its encoding: it can be sent with
+// Affine and FromAffine. Mul and MultiExp handle it, as the points of MapToCurveG2.
 func (k *KyberG2) Embed(data []byte, rand cipher.Stream) kyber.Point {
-	panic("bls12-381: unsupported operation")
+	k.backend = backendOrDefault(k.backend)
+	p, err := k.backend.g2().fromUncompressed(embedG2(data, rand))
+	if err != nil {
+		panic(err)
+	}
+	k.p, k.outside = p, !p.inSubgroup()
+	return k
 }
 
+// Data returns the data embedded in k by Embed, or an error if k does not hold embedded data.
 func (k *KyberG2) Data() ([]byte, error) {
-	panic("bls12-381: unsupported operation")
+	return embeddedData(k.pointIn(backendOrDefault(k.backend)).uncompressed(), 2)
 }
 
 func (k *KyberG2) Add(a, b kyber.Point) kyber.Point {
 	aa := a.(*KyberG2)
 	bb := b.(*KyberG2)
-	bls12381.NewG2().Add(k.p, aa.p, bb.p)
+	k.mix(aa, bb)
+	k.setIfEmpty().p.add(k.in(aa), k.in(bb))
+	k.outside = aa.outside || bb.outside
 	return k
 }
 
 func (k *KyberG2) Sub(a, b kyber.Point) kyber.Point {
 	aa := a.(*KyberG2)
 	bb := b.(*KyberG2)
-	bls12381.NewG2().Sub(k.p, aa.p, bb.p)
+	k.mix(aa, bb)
+	k.setIfEmpty().p.sub(k.in(aa), k.in(bb))
+	k.outside = aa.outside || bb.outside
 	return k
 }
 
 func (k *KyberG2) Neg(a kyber.Point) kyber.Point {
 	aa := a.(*KyberG2)
-	bls12381.NewG2().Neg(k.p, aa.p)
+	k.mix(aa)
+	k.setIfEmpty().p.neg(k.in(aa))
+	k.outside = aa.outside
 	return k
 }
 
 func (k *KyberG2) Mul(s kyber.Scalar, q kyber.Point) kyber.Point {
 	if q == nil {
-		q = NullKyberG2(k.dst...).Base()
+		q = k.Base()
+	}
+	qq := q.(*KyberG2)
+	k.mix(qq)
+	k.setIfEmpty()
+	if qq.outside {
+		k.p.mulAny(k.in(qq), &s.(*mod.Int).V)
+	} else {
+		k.p.mul(k.in(qq), &s.(*mod.Int).V)
 	}
-	bls12381.NewG2().MulScalarBig(k.p, q.(*KyberG2).p, &s.(*mod.Int).V)
+	k.outside = qq.outside
 	return k
 }
 
-// MarshalBinary returns a compressed point, without any domain separation tag information
+// MarshalBinary returns a compressed point, without any domain separation tag information,
+// see MarshalWithDomain to keep it.
 func (k *KyberG2) MarshalBinary() ([]byte, error) {
-	// we need to clone the point because of https://github.com/kilic/bls12-381/issues/37
-	// in order to avoid risks of race conditions.
-	t := new(bls12381.PointG2).Set(k.p)
-	return bls12381.NewG2().ToCompressed(t), nil
+	return k.pointIn(backendOrDefault(k.backend)).compressed(), nil
 }
 
-// UnmarshalBinary populates the point from a compressed point representation.
+// UnmarshalBinary populates the point from a compressed point representation. The point is left
+// unchanged if the encoding is rejected, with one of the decoding errors such as ErrNotInSubgroup.
 func (k *KyberG2) UnmarshalBinary(buff []byte) error {
-	var err error
-	k.p, err = bls12381.NewG2().FromCompressed(buff)
-	return err
+	// zero values, e.g. allocated by encoding/json, use the default backend
+	k.backend = backendOrDefault(k.backend)
+	p, err := decodeCompressed(k.backend.g2(), buff, k.MarshalSize())
+	if err != nil {
+		return err
+	}
+	k.p, k.outside = p, false
+	return nil
+}
+
+// MarshalWithDomain returns the compressed point along with the DST and the expander used by Hash, so that
+// UnmarshalWithDomain restores a point Equal to k.
+func (k *KyberG2) MarshalWithDomain() ([]byte, error) {
+	buf, _ := k.MarshalBinary()
+	return marshalDomain(k.domain(), k.exp, buf)
+}
+
+// UnmarshalWithDomain populates the point and its domain from the encoding written by MarshalWithDomain.
+// The expander must be one of the expanders of this package, or the expander of k.
+func (k *KyberG2) UnmarshalWithDomain(buf []byte) error {
+	dst, exp, point, err := unmarshalDomain(buf, k.exp)
+	if err != nil {
+		return err
+	}
+	if err := k.UnmarshalBinary(point); err != nil {
+		return err
+	}
+	q := newKyberG2(k.p, dst, exp)
+	k.dst, k.exp = q.dst, q.exp
+	return nil
 }
 
 // MarshalTo writes a compressed point to the Writer, without any domain separation tag information
@@ -155,17 +276,45 @@ func (k *KyberG2) String() string {
 	return "bls12-381.G2: " + hex.EncodeToString(b)
 }
 
-func (k *KyberG2) Hash(m []byte) kyber.Point {
-	domain := domainG2
+// domain returns the DST used by Hash.
+func (k *KyberG2) domain() []byte {
 	// We treat a 0 len dst as the default value as per the RFC "Tags MUST have nonzero length"
 	if len(k.dst) != 0 {
-		domain = k.dst
+		return k.dst
 	}
-	pg2, _ := bls12381.NewG2().HashToCurve(m, domain)
-	k.p = pg2
+	return DomainG2WithExpander(k.exp)
+}
+
+func (k *KyberG2) Hash(m []byte) kyber.Point {
+	k.setIfEmpty()
+	domain := k.domain()
+	switch {
+	case k.exp != nil || len(domain) > 255 && k.oversize:
+		// backends do not implement the other expanders, nor the hashing of tags longer than 255 bytes
+		k.p, _ = hashToCurveG2(k.backend, m, domain, k.exp)
+	case len(domain) > 255:
+		// as by previous releases, see WithOversizeDomains
+		k.p, _ = hashToCurveG2(k.backend, m, domain, expanderXMDTruncated)
+	default:
+		k.p, _ = k.backend.g2().hashToCurve(m, domain)
+	}
+	k.outside = false
+	return k
+}
+
+// ClearCofactor sets k to the image of q under clear_cofactor from RFC 9380 section 7, which maps any
+// point of E2 to G2, e.g. the result of MapToCurveG2.
+func (k *KyberG2) ClearCofactor(q kyber.Point) kyber.Point {
+	qq := q.(*KyberG2)
+	k.mix(qq)
+	k.setIfEmpty().p.set(k.in(qq))
+	k.p.clearCofactor()
+	k.outside = false
 	return k
 }
 
+// IsInCorrectGroup returns whether k is a point of G2. It returns false when k does not hold a point,
+// e.g. a zero value.
 func (k *KyberG2) IsInCorrectGroup() bool {
-	return bls12381.NewG2().InCorrectSubgroup(k.p)
+	return k.p != nil && k.p.inSubgroup()
 }
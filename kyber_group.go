@@ -11,7 +11,6 @@ import (
 	"github.com/drand/kyber/pairing"
 	"github.com/drand/kyber/util/random"
 	"github.com/drand/kyber/xof/blake2xb"
-	bls12381 "github.com/kilic/bls12-381"
 )
 
 // GroupChecker allows to verify if a Point is in the correct group or not. For
@@ -69,25 +68,55 @@ func (g *groupBls) RandomStream() cipher.Stream {
 }
 
 func NewGroupG1(dst ...byte) kyber.Group {
+	return newGroupG1(defaultBackend, nil, dst, false, false)
+}
+
+// NewGroupG1WithExpander returns the G1 group whose points hash to the curve with the given expander and DST.
+func NewGroupG1WithExpander(exp Expander, dst ...byte) kyber.Group {
+	return newGroupG1(defaultBackend, exp, dst, false, false)
+}
+
+func newGroupG1(b Backend, exp Expander, dst []byte, strict, oversize bool) kyber.Group {
 	return &groupBls{
-		str:      "bls12-381.G1",
-		newPoint: func() kyber.Point { return NullKyberG1(dst...) },
-		isPrime:  true,
+		str: "bls12-381.G1",
+		newPoint: func() kyber.Point {
+			p := newKyberG1(b.g1().zero(), dst, exp)
+			p.strict, p.oversize = strict, oversize
+			return p
+		},
+		isPrime: true,
 	}
 }
 
 func NewGroupG2(dst ...byte) kyber.Group {
+	return newGroupG2(defaultBackend, nil, dst, false, false)
+}
+
+// NewGroupG2WithExpander returns the G2 group whose points hash to the curve with the given expander and DST.
+func NewGroupG2WithExpander(exp Expander, dst ...byte) kyber.Group {
+	return newGroupG2(defaultBackend, exp, dst, false, false)
+}
+
+func newGroupG2(b Backend, exp Expander, dst []byte, strict, oversize bool) kyber.Group {
 	return &groupBls{
-		str:      "bls12-381.G2",
-		newPoint: func() kyber.Point { return NullKyberG2(dst...) },
-		isPrime:  false,
+		str: "bls12-381.G2",
+		newPoint: func() kyber.Point {
+			p := newKyberG2(b.g2().zero(), dst, exp)
+			p.strict, p.oversize = strict, oversize
+			return p
+		},
+		isPrime: false,
 	}
 }
 
 func NewGroupGT() kyber.Group {
+	return newGroupGT(defaultBackend)
+}
+
+func newGroupGT(b Backend) kyber.Group {
 	return &groupBls{
 		str:      "bls12-381.GT",
-		newPoint: func() kyber.Point { return newEmptyGT() },
+		newPoint: func() kyber.Point { return newKyberGT(b.gtOne()) },
 		isPrime:  false,
 	}
 }
@@ -95,18 +124,76 @@ func NewGroupGT() kyber.Group {
 type Suite struct {
 	domainG1 []byte
 	domainG2 []byte
+	expander Expander
+	backend  Backend
+	// strictDomains is set by WithStrictDomains
+	strictDomains bool
+	// oversizeDomains is set by WithOversizeDomains
+	oversizeDomains bool
+}
+
+// SuiteOption configures a Suite when passed to its constructors.
+type SuiteOption func(*Suite)
+
+// WithBackend selects the implementation of the curve arithmetic used by the suite, BackendKilic by default.
+// The backend does not change the behavior or the encodings of the points.
+func WithBackend(b Backend) SuiteOption {
+	return func(s *Suite) {
+		s.backend = b
+	}
+}
+
+// WithStrictDomains makes the points of the suite panic with ErrDomainMismatch when they are combined in
+// Add, Sub, Neg, Mul or ClearCofactor with points of another domain, i.e. hashing to the curve with another
+// DST or expander. Pair and ValidatePairing panic as well when given points which do not have the domains of
+// the suite. The Safe methods, see CheckedPoint, return the error instead.
+//
+// Without it, the result of an operation keeps the domain of the receiver, whatever the domains of the
+// operands. In both cases, Set and Clone copy the domain of the point, and Equal only holds for points of the
+// same domain.
+func WithStrictDomains() SuiteOption {
+	return func(s *Suite) {
+		s.strictDomains = true
+	}
+}
+
+// WithOversizeDomains makes the points of the suite hash to the curve with DSTs longer than 255 bytes as
+// specified by RFC 9380 section 5.3.3, hashing the DST first.
+//
+// Without it, such DSTs are hashed with the default expander as by previous releases, which relied on kilic:
+// the DST is used as is, and only the low byte of its length is written in DST_prime. This is not compliant
+// with RFC 9380, but keeps the signatures of existing keys and messages valid. The other expanders always
+// follow RFC 9380, and the DSTs of at most 255 bytes are not affected.
+func WithOversizeDomains() SuiteOption {
+	return func(s *Suite) {
+		s.oversizeDomains = true
+	}
+}
+
+func newSuite(s *Suite, opts []SuiteOption) *Suite {
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // NewBLS12381Suite is the same as calling NewBLS12381SuiteWithDST(nil, nil): it uses the default domain separation
 // tags for its Hash To Curve functions.
-func NewBLS12381Suite() pairing.Suite {
-	return &Suite{}
+func NewBLS12381Suite(opts ...SuiteOption) pairing.Suite {
+	return newSuite(&Suite{}, opts)
 }
 
 // NewBLS12381SuiteWithDST allows you to set your own domain separation tags to be used by the Hash To Curve functions.
 // Since the DST shouldn't be 0 len, if you provide nil or a 0 len byte array, it will use the RFC default values.
-func NewBLS12381SuiteWithDST(DomainG1, DomainG2 []byte) pairing.Suite {
-	return &Suite{domainG1: DomainG1, domainG2: DomainG2}
+func NewBLS12381SuiteWithDST(DomainG1, DomainG2 []byte, opts ...SuiteOption) pairing.Suite {
+	return newSuite(&Suite{domainG1: DomainG1, domainG2: DomainG2}, opts)
+}
+
+// NewBLS12381SuiteWithExpander is like NewBLS12381SuiteWithDST, but its Hash To Curve functions use the given
+// expand_message variant, e.g. ExpanderXOFSHAKE256 for the BLS12381G1_XOF:SHAKE256_SSWU_RO_ suite.
+// If you provide nil or a 0 len DST, it will use the default BLS signature DST of that suite, see DomainG1WithExpander.
+func NewBLS12381SuiteWithExpander(exp Expander, DomainG1, DomainG2 []byte, opts ...SuiteOption) pairing.Suite {
+	return newSuite(&Suite{domainG1: DomainG1, domainG2: DomainG2, expander: exp}, opts)
 }
 
 func (s *Suite) SetDomainG1(dst []byte) {
@@ -114,7 +201,7 @@ func (s *Suite) SetDomainG1(dst []byte) {
 }
 
 func (s *Suite) G1() kyber.Group {
-	return NewGroupG1(s.domainG1...)
+	return newGroupG1(s.Backend(), s.expander, s.domainG1, s.strictDomains, s.oversizeDomains)
 }
 
 func (s *Suite) SetDomainG2(dst []byte) {
@@ -122,47 +209,152 @@ func (s *Suite) SetDomainG2(dst []byte) {
 }
 
 func (s *Suite) G2() kyber.Group {
-	return NewGroupG2(s.domainG2...)
+	return newGroupG2(s.Backend(), s.expander, s.domainG2, s.strictDomains, s.oversizeDomains)
+}
+
+// SetExpander sets the expand_message variant used by the Hash To Curve functions of both groups.
+func (s *Suite) SetExpander(exp Expander) {
+	s.expander = exp
 }
 
 func (s *Suite) GT() kyber.Group {
-	return NewGroupGT()
+	return newGroupGT(s.Backend())
+}
+
+// Backend returns the implementation of the curve arithmetic used by the suite.
+func (s *Suite) Backend() Backend {
+	return backendOrDefault(s.backend)
 }
 
 // ValidatePairing implements the `pairing.Suite` interface
 func (s *Suite) ValidatePairing(p1, p2, p3, p4 kyber.Point) bool {
-	e := bls12381.NewEngine()
-	// we need to clone the point because of https://github.com/kilic/bls12-381/issues/37
-	// in order to avoid risks of race conditions.
-	g1point := new(bls12381.PointG1).Set(p1.(*KyberG1).p)
-	g2point := new(bls12381.PointG2).Set(p2.(*KyberG2).p)
-	g1point2 := new(bls12381.PointG1).Set(p3.(*KyberG1).p)
-	g2point2 := new(bls12381.PointG2).Set(p4.(*KyberG2).p)
-	e.AddPair(g1point, g2point)
-	e.AddPairInv(g1point2, g2point2)
-	return e.Check()
+	if err := s.checkDomains([]kyber.Point{p1, p3}, []kyber.Point{p2, p4}); err != nil {
+		panic(err)
+	}
+	b := s.Backend()
+	// e(p1, p2) = e(p3, p4) if and only if e(p1, p2) * e(-p3, p4) = 1
+	negP3 := b.g1().zero()
+	negP3.neg(p3.(*KyberG1).pointIn(b))
+	return b.pairingCheck(
+		[]point{p1.(*KyberG1).pointIn(b), negP3},
+		[]point{p2.(*KyberG2).pointIn(b), p4.(*KyberG2).pointIn(b)},
+	)
+}
+
+// PairingCheck returns whether the product of the pairings e(g1[i], g2[i]) is one, with a single final
+// exponentiation. It holds for empty slices, and panics if they have different lengths.
+func (s *Suite) PairingCheck(g1, g2 []kyber.Point) bool {
+	if len(g1) != len(g2) {
+		panic("bls12-381: PairingCheck with slices of different lengths")
+	}
+	if len(g1) == 0 {
+		return true
+	}
+	if err := s.checkDomains(g1, g2); err != nil {
+		panic(err)
+	}
+	b := s.Backend()
+	ps := make([]point, len(g1))
+	qs := make([]point, len(g2))
+	for i := range g1 {
+		ps[i] = g1[i].(*KyberG1).pointIn(b)
+		qs[i] = g2[i].(*KyberG2).pointIn(b)
+	}
+	return b.pairingCheck(ps, qs)
+}
+
+// CheckPairings returns whether the product of the pairings e(g1[i], g2[i]) is one, with PairingCheck for
+// the suites of this package and with Pair otherwise.
+func CheckPairings(s pairing.Suite, g1, g2 []kyber.Point) bool {
+	if c, ok := s.(interface {
+		PairingCheck(g1, g2 []kyber.Point) bool
+	}); ok {
+		return c.PairingCheck(g1, g2)
+	}
+	if len(g1) != len(g2) {
+		panic("bls12-381: PairingCheck with slices of different lengths")
+	}
+	gt := s.GT()
+	e := gt.Point().Null()
+	for i := range g1 {
+		e.Add(e, s.Pair(g1[i], g2[i]))
+	}
+	return e.Equal(gt.Point().Null())
 }
 
 func (s *Suite) Pair(p1, p2 kyber.Point) kyber.Point {
-	e := bls12381.NewEngine()
-	g1point := p1.(*KyberG1).p
-	g2point := p2.(*KyberG2).p
-	return newKyberGT(e.AddPair(g1point, g2point).Result())
+	if err := s.checkDomains([]kyber.Point{p1}, []kyber.Point{p2}); err != nil {
+		panic(err)
+	}
+	b := s.Backend()
+	g1point := p1.(*KyberG1).pointIn(b)
+	g2point := p2.(*KyberG2).pointIn(b)
+	return newKyberGT(b.pair([]point{g1point}, []point{g2point}))
 }
 
-// New implements the kyber.Encoding interface.
+// checkDomains returns ErrDomainMismatch if the suite has strict domains, and the points of g1 and g2 do not
+// have the domains of its G1 and G2 points.
+func (s *Suite) checkDomains(g1, g2 []kyber.Point) error {
+	if !s.strictDomains {
+		return nil
+	}
+	k1 := s.G1().Point().(*KyberG1)
+	for _, p := range g1 {
+		if _, err := k1.check(p); err != nil {
+			return err
+		}
+	}
+	k2 := s.G2().Point().(*KyberG2)
+	for _, p := range g2 {
+		if _, err := k2.check(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var (
+	tScalar  = reflect.TypeOf((*kyber.Scalar)(nil)).Elem()
+	tPointG1 = reflect.TypeOf((*KyberG1)(nil))
+	tPointG2 = reflect.TypeOf((*KyberG2)(nil))
+	tPointGT = reflect.TypeOf((*KyberGT)(nil))
+)
+
+// New implements the kyber.Encoding interface. It returns a new scalar for kyber.Scalar, and a new point of
+// the suite for *KyberG1, *KyberG2 and *KyberGT. It returns nil for kyber.Point, since the suite has several
+// groups: kyber.Point values must hold a point of the right group before Read.
 func (s *Suite) New(t reflect.Type) interface{} {
-	panic("Suite.Encoding: deprecated in drand")
+	switch t {
+	case tScalar:
+		return s.G1().Scalar()
+	case tPointG1:
+		return s.G1().Point()
+	case tPointG2:
+		return s.G2().Point()
+	case tPointGT:
+		return s.GT().Point()
+	}
+	return nil
 }
 
-// Read is the default implementation of kyber.Encoding interface Read.
+// Read implements the kyber.Encoding interface, see encoding.go.
 func (s *Suite) Read(r io.Reader, objs ...interface{}) error {
-	panic("Suite.Read(): deprecated in drand")
+	for _, obj := range objs {
+		if err := s.read(r, reflect.ValueOf(obj)); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-// Write is the default implementation of kyber.Encoding interface Write.
+// Write implements the kyber.Encoding interface, see encoding.go.
 func (s *Suite) Write(w io.Writer, objs ...interface{}) error {
-	panic("Suite.Write(): deprecated in drand")
+	for _, obj := range objs {
+		if err := write(w, reflect.ValueOf(obj)); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // Hash returns a newly instantiated sha256 hash function.
@@ -7,30 +7,37 @@ import (
 
 	"github.com/drand/kyber"
 	"github.com/drand/kyber/group/mod"
-	bls12381 "github.com/kilic/bls12-381"
 )
 
 type KyberGT struct {
-	f *bls12381.E
+	f gtElement
+	// backend holding f, set by UnmarshalBinary for zero values
+	backend Backend
 }
 
 func newEmptyGT() *KyberGT {
-	return newKyberGT(bls12381.NewGT().New())
+	return newKyberGT(defaultBackend.gtOne())
 }
-func newKyberGT(f *bls12381.E) *KyberGT {
+func newKyberGT(f gtElement) *KyberGT {
 	return &KyberGT{
-		f: f,
+		f:       f,
+		backend: f.backend(),
 	}
 }
 
+// in returns the element of q in the representation of the backend of k.
+func (k *KyberGT) in(q kyber.Point) gtElement {
+	return convertGT(k.backend, q.(*KyberGT).f)
+}
+
 func (k *KyberGT) Equal(kk kyber.Point) bool {
-	return k.f.Equal(kk.(*KyberGT).f)
+	return k.f.equal(k.in(kk))
 }
 
 func (k *KyberGT) Null() kyber.Point {
 	// One since we deal with Gt elements as a multiplicative group only
 	// i.e. Add in kyber -> mul in kilic/, Neg in kyber -> inverse in kilic/ etc
-	k.f = bls12381.NewGT().New()
+	k.f = k.backend.gtOne()
 	return k
 }
 
@@ -43,43 +50,39 @@ func (k *KyberGT) Pick(rand cipher.Stream) kyber.Point {
 }
 
 func (k *KyberGT) Set(q kyber.Point) kyber.Point {
-	k.f.Set(q.(*KyberGT).f)
+	k.f.set(k.in(q))
 	return k
 }
 
 func (k *KyberGT) Clone() kyber.Point {
-	kk := newEmptyGT()
+	kk := newKyberGT(k.backend.gtOne())
 	kk.Set(k)
 	return kk
 }
 
 func (k *KyberGT) Add(a, b kyber.Point) kyber.Point {
-	aa := a.(*KyberGT)
-	bb := b.(*KyberGT)
-	bls12381.NewGT().Mul(k.f, aa.f, bb.f)
+	k.f.mul(k.in(a), k.in(b))
 	return k
 }
 
 func (k *KyberGT) Sub(a, b kyber.Point) kyber.Point {
-	nb := newEmptyGT().Neg(b)
-	return newEmptyGT().Add(a, nb)
+	nb := newKyberGT(k.backend.gtOne()).Neg(b)
+	return k.Add(a, nb)
 }
 
 func (k *KyberGT) Neg(q kyber.Point) kyber.Point {
-	qq := q.(*KyberGT)
-	bls12381.NewGT().Inverse(k.f, qq.f)
+	k.f.inverse(k.in(q))
 	return k
 }
 
 func (k *KyberGT) Mul(s kyber.Scalar, q kyber.Point) kyber.Point {
 	v := s.(*mod.Int).V
-	qq := q.(*KyberGT)
-	bls12381.NewGT().Exp(k.f, qq.f, &v)
+	k.f.exp(k.in(q), &v)
 	return k
 }
 
 func (k *KyberGT) MarshalBinary() ([]byte, error) {
-	return bls12381.NewGT().ToBytes(k.f), nil
+	return k.f.bytes(), nil
 }
 
 func (k *KyberGT) MarshalTo(w io.Writer) (int, error) {
@@ -91,9 +94,14 @@ func (k *KyberGT) MarshalTo(w io.Writer) (int, error) {
 }
 
 func (k *KyberGT) UnmarshalBinary(buf []byte) error {
-	fe12, err := bls12381.NewGT().FromBytes(buf)
-	k.f = fe12
-	return err
+	// zero values, e.g. allocated by encoding/json, use the default backend
+	k.backend = backendOrDefault(k.backend)
+	f, err := decodeGT(k.backend, buf)
+	if err != nil {
+		return err
+	}
+	k.f = f
+	return nil
 }
 
 func (k *KyberGT) UnmarshalFrom(r io.Reader) (int, error) {
@@ -125,3 +133,9 @@ func (k *KyberGT) Embed(data []byte, rand cipher.Stream) kyber.Point {
 func (k *KyberGT) Data() ([]byte, error) {
 	panic("bls12-381.GT.Data(): unsupported operation")
 }
+
+// IsInCorrectGroup returns whether k is an element of the subgroup of order r of Fp12. It is false for a
+// zero value. UnmarshalBinary rejects the other elements with ErrNotInSubgroup, leaving k unchanged.
+func (k *KyberGT) IsInCorrectGroup() bool {
+	return k.f != nil && k.f.inSubgroup()
+}
@@ -250,6 +250,14 @@ func testGroup(t *testing.T, g kyber.Group, rand cipher.Stream) []kyber.Point {
 		}
 	}
 
+	// Test embedding data
+	testEmbed(t, g, rand, &points, "Hi!")
+	testEmbed(t, g, rand, &points, "The quick brown fox jumps over the lazy dog")
+	testEmbed(t, g, rand, &points, "The quick brown fox jumps over the lazy dog, then over the lazy cat, then over the lazy hen")
+	if _, err := g.Point().Base().Data(); err == nil {
+		t.Fatalf("Data of a point without embedded data succeeded")
+	}
+
 	// Test that we can marshal/ unmarshal null point
 	pzero = g.Point().Null()
 	b, _ := pzero.MarshalBinary()
@@ -262,6 +270,42 @@ func testGroup(t *testing.T, g kyber.Group, rand cipher.Stream) []kyber.Point {
 	return points
 }
 
+func testEmbed(t *testing.T, g kyber.Group, rand cipher.Stream, points *[]kyber.Point, s string) {
+	b := []byte(s)
+
+	p := g.Point().Embed(b, rand)
+	x, err := p.Data()
+	if err != nil {
+		t.Fatalf("Point extraction failed for %v: %v", p, err)
+	}
+	max := g.Point().EmbedLen()
+	if max > len(b) {
+		max = len(b)
+	}
+	if !bytes.Equal(append(x, b[max:]...), b) {
+		t.Fatalf("Point embedding corrupted the data")
+	}
+	if !p.(interface{ IsOnCurve() bool }).IsOnCurve() {
+		t.Fatalf("Point embedding left the curve: %v", p)
+	}
+
+	// the data survives an ElGamal-style encryption and decryption
+	key := g.Point().Pick(rand)
+	c := g.Point().Add(p, key)
+	x, err = g.Point().Sub(c, key).Data()
+	require.NoError(t, err)
+	require.Equal(t, b[:len(x)], x)
+	// and the exchange of the affine coordinates
+	ax, ay := p.(interface{ Affine() ([]byte, []byte) }).Affine()
+	q := g.Point().(interface{ FromAffine(x, y []byte) error })
+	require.NoError(t, q.FromAffine(ax, ay))
+	x, err = q.(kyber.Point).Data()
+	require.NoError(t, err)
+	require.Equal(t, b[:len(x)], x)
+
+	*points = append(*points, p)
+}
+
 // GroupTest applies a generic set of validation tests to a cryptographic Group.
 func GroupTest(t *testing.T, g kyber.Group) {
 	testGroup(t, g, random.New())
@@ -508,6 +552,7 @@ func TestImplementInterfaces(t *testing.T) {
 	// var _ kyber.HashablePoint = &KyberGT{} // GT is not hashable for now
 	var _ kyber.Group = &groupBls{}
 	var _ pairing.Suite = &Suite{}
+	var _ kyber.Encoding = &Suite{}
 }
 
 func TestSuiteWithDST(t *testing.T) {
@@ -563,3 +608,80 @@ func TestExplicitDefaultDST(t *testing.T) {
 		t.Fatal("Default G2 DST should be represented internally as nil. Got:", string(p.dst))
 	}
 }
+
+func TestDomainPropagation(t *testing.T) {
+	p := NullKyberG1([]byte("some DST")...).Pick(random.New())
+	q := NullKyberG1().Set(p)
+	require.True(t, q.Equal(p))
+	require.True(t, p.Clone().Equal(p))
+	require.True(t, p.Null().Equal(NullKyberG1([]byte("some DST")...)))
+	// the result of the arithmetic keeps the domain of the receiver
+	require.False(t, NullKyberG1().Add(p, p).Equal(p.Clone().Add(p, p)))
+	require.Equal(t, NullKyberG1().Add(p, p).String(), p.Clone().Add(p, p).String())
+}
+
+func TestStrictDomains(t *testing.T) {
+	suite := NewBLS12381Suite(WithStrictDomains())
+	other := NewBLS12381SuiteWithDST([]byte("other G1 DST"), []byte("other G2 DST"))
+	test.SchemeTesting(t, bls.NewSchemeOnG2(suite))
+
+	p, q := suite.G1().Point().Pick(random.New()), other.G1().Point().Pick(random.New())
+	s := suite.G1().Scalar().Pick(random.New())
+	require.PanicsWithError(t, ErrDomainMismatch.Error()+`: "BLS_SIG_BLS12381G1_XMD:SHA-256_SSWU_RO_NUL_" and "other G1 DST"`,
+		func() { suite.G1().Point().Add(p, q) })
+	require.Panics(t, func() { suite.G1().Point().Sub(q, p) })
+	require.Panics(t, func() { suite.G1().Point().Neg(q) })
+	require.Panics(t, func() { suite.G1().Point().Mul(s, q) })
+	// points of a non-strict suite panic when mixed with strict ones
+	require.Panics(t, func() { other.G1().Point().Add(q, p) })
+	require.NotPanics(t, func() { other.G1().Point().Add(q, q) })
+	// Set takes the domain of its argument
+	require.True(t, suite.G1().Point().Set(q).Equal(q))
+
+	// keys of another domain are rejected when verifying
+	scheme := bls.NewSchemeOnG2(suite)
+	sk, _ := scheme.NewKeyPair(random.New())
+	sig, err := scheme.Sign(sk, []byte("msg"))
+	require.NoError(t, err)
+	pk := other.G1().Point().Mul(sk, nil)
+	require.Panics(t, func() { _ = scheme.Verify(pk, []byte("msg"), sig) })
+	require.NoError(t, scheme.Verify(suite.G1().Point().Mul(sk, nil), []byte("msg"), sig))
+
+	_, err = suite.(*Suite).SafePair(pk, suite.G2().Point().Base())
+	require.ErrorIs(t, err, ErrDomainMismatch)
+	_, err = suite.(*Suite).SafeValidatePairing(pk, suite.G2().Point(), pk, suite.G2().Point())
+	require.ErrorIs(t, err, ErrDomainMismatch)
+}
+
+func TestZeroValue(t *testing.T) {
+	for _, tc := range []struct {
+		zero  func() kyber.Point
+		group kyber.Group
+	}{
+		{func() kyber.Point { return new(KyberG1) }, NewGroupG1()},
+		{func() kyber.Point { return new(KyberG2) }, NewGroupG2()},
+	} {
+		null, base := tc.group.Point().Null(), tc.group.Point().Base()
+		require.True(t, tc.zero().Null().Equal(null))
+		require.True(t, tc.zero().Base().Equal(base))
+		require.True(t, tc.zero().Equal(null))
+		require.True(t, tc.zero().Clone().Equal(null))
+		b, err := tc.zero().MarshalBinary()
+		require.NoError(t, err)
+		nb, err := null.MarshalBinary()
+		require.NoError(t, err)
+		require.Equal(t, nb, b)
+
+		msg := []byte("zero value")
+		h := tc.group.Point().(kyber.HashablePoint).Hash(msg)
+		require.True(t, tc.zero().(kyber.HashablePoint).Hash(msg).Equal(h))
+		require.True(t, tc.zero().Set(h).Equal(h))
+		require.True(t, tc.zero().Add(h, base).Equal(tc.group.Point().Add(h, base)))
+		require.True(t, tc.zero().Sub(h, base).Equal(tc.group.Point().Sub(h, base)))
+		require.True(t, tc.zero().Neg(h).Equal(tc.group.Point().Neg(h)))
+		s := tc.group.Scalar().Pick(random.New())
+		require.True(t, tc.zero().Mul(s, nil).Equal(tc.group.Point().Mul(s, nil)))
+		require.True(t, tc.zero().Mul(s, h).Equal(tc.group.Point().Mul(s, h)))
+		require.True(t, LinearCombination(tc.zero(), []kyber.Point{h}, []kyber.Scalar{s}).Equal(tc.group.Point().Mul(s, h)))
+	}
+}
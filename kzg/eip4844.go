@@ -0,0 +1,272 @@
+package kzg
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/drand/kyber"
+	bls "github.com/drand/kyber-bls12381"
+	"github.com/drand/kyber/group/mod"
+)
+
+// The functions of the polynomial commitments of EIP-4844, as specified in the Deneb polynomial-commitments.md
+// of the Ethereum consensus specification. Blobs hold len(G1Lagrange) big-endian scalars of 32 bytes, the
+// evaluations of a polynomial at the roots of unity in bit-reversed order, 4096 with the Ethereum setup.
+// Commitments and proofs are compressed points of G1.
+
+const (
+	// ScalarSize is the size of the scalars in blobs, evaluation points and evaluations.
+	ScalarSize = 32
+	// PointSize is the size of commitments and proofs.
+	PointSize = 48
+
+	blobDomain  = "FSBLOBVERIFY_V1_"
+	batchDomain = "RCKZGBATCH___V1_"
+)
+
+var (
+	errBlob       = errors.New("kzg: invalid blob")
+	errEncoding   = errors.New("kzg: invalid encoding")
+	errNoLagrange = errors.New("kzg: the trusted setup has no Lagrange powers")
+)
+
+// BlobToKZGCommitment returns the commitment to blob, as blob_to_kzg_commitment.
+func (s *Setup) BlobToKZGCommitment(blob []byte) ([]byte, error) {
+	poly, err := s.blobToPolynomial(blob)
+	if err != nil {
+		return nil, err
+	}
+	return bls.LinearCombination(s.suite.G1().Point(), s.lagrangeBRP, poly).MarshalBinary()
+}
+
+// ComputeKZGProof returns the proof that the polynomial of blob is y at z, along with y, as
+// compute_kzg_proof.
+func (s *Setup) ComputeKZGProof(blob, z []byte) (proof, y []byte, err error) {
+	poly, err := s.blobToPolynomial(blob)
+	if err != nil {
+		return nil, nil, err
+	}
+	zz, err := s.bytesToScalar(z)
+	if err != nil {
+		return nil, nil, err
+	}
+	p, yy := s.computeProof(poly, zz)
+	if proof, err = p.MarshalBinary(); err != nil {
+		return nil, nil, err
+	}
+	if y, err = yy.MarshalBinary(); err != nil {
+		return nil, nil, err
+	}
+	return proof, y, nil
+}
+
+// ComputeBlobKZGProof returns the proof that the polynomial of blob has its committed value at the
+// Fiat-Shamir challenge of blob and commitment, as compute_blob_kzg_proof.
+func (s *Setup) ComputeBlobKZGProof(blob, commitment []byte) ([]byte, error) {
+	poly, err := s.blobToPolynomial(blob)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.bytesToPoint(commitment); err != nil {
+		return nil, err
+	}
+	proof, _ := s.computeProof(poly, s.challenge(blob, commitment))
+	return proof.MarshalBinary()
+}
+
+// VerifyKZGProof checks the proof that the committed polynomial is y at z, as verify_kzg_proof. It returns
+// ErrInvalidProof if the proof does not verify, and another error for malformed inputs.
+func (s *Setup) VerifyKZGProof(commitment, z, y, proof []byte) error {
+	if len(s.G2) < 2 {
+		return errSetupTooBig
+	}
+	c, err := s.bytesToPoint(commitment)
+	if err != nil {
+		return err
+	}
+	zz, err := s.bytesToScalar(z)
+	if err != nil {
+		return err
+	}
+	yy, err := s.bytesToScalar(y)
+	if err != nil {
+		return err
+	}
+	p, err := s.bytesToPoint(proof)
+	if err != nil {
+		return err
+	}
+	return s.Verify(c, zz, yy, p)
+}
+
+// VerifyBlobKZGProofBatch checks the proofs of the blobs with the commitments, as computed by
+// ComputeBlobKZGProof, as verify_blob_kzg_proof_batch. It returns ErrInvalidProof if a proof does not verify,
+// and another error for malformed inputs.
+func (s *Setup) VerifyBlobKZGProofBatch(blobs, commitments, proofs [][]byte) error {
+	if len(blobs) != len(commitments) || len(commitments) != len(proofs) {
+		return errLength
+	}
+	g1 := s.suite.G1()
+	cs := make([]kyber.Point, len(blobs))
+	zs := make([]kyber.Scalar, len(blobs))
+	ys := make([]kyber.Scalar, len(blobs))
+	ps := make([]kyber.Point, len(blobs))
+	for i, blob := range blobs {
+		poly, err := s.blobToPolynomial(blob)
+		if err != nil {
+			return err
+		}
+		if cs[i], err = s.bytesToPoint(commitments[i]); err != nil {
+			return err
+		}
+		if ps[i], err = s.bytesToPoint(proofs[i]); err != nil {
+			return err
+		}
+		zs[i] = s.challenge(blob, commitments[i])
+		ys[i] = s.evaluate(poly, zs[i])
+	}
+
+	// the combination scalar is derived from all the inputs, as verify_kzg_proof_batch
+	var data bytes.Buffer
+	data.WriteString(batchDomain)
+	_ = binary.Write(&data, binary.BigEndian, uint64(len(s.lagrangeBRP)))
+	_ = binary.Write(&data, binary.BigEndian, uint64(len(blobs)))
+	for i := range blobs {
+		data.Write(commitments[i])
+		z, _ := zs[i].MarshalBinary()
+		y, _ := ys[i].MarshalBinary()
+		data.Write(z)
+		data.Write(y)
+		data.Write(proofs[i])
+	}
+	return s.verifyBatch(cs, zs, ys, ps, hashToScalar(g1, data.Bytes()))
+}
+
+// blobToPolynomial returns the evaluations held by blob, in bit-reversed order.
+func (s *Setup) blobToPolynomial(blob []byte) ([]kyber.Scalar, error) {
+	n := len(s.lagrangeBRP)
+	if n == 0 {
+		return nil, errNoLagrange
+	}
+	if len(blob) != n*ScalarSize {
+		return nil, fmt.Errorf("%w: %d bytes instead of %d", errBlob, len(blob), n*ScalarSize)
+	}
+	poly := make([]kyber.Scalar, n)
+	for i := range poly {
+		var err error
+		if poly[i], err = s.bytesToScalar(blob[i*ScalarSize : (i+1)*ScalarSize]); err != nil {
+			return nil, fmt.Errorf("%w: element %d: %v", errBlob, i, err)
+		}
+	}
+	return poly, nil
+}
+
+// bytesToScalar decodes a canonical big-endian scalar.
+func (s *Setup) bytesToScalar(buf []byte) (kyber.Scalar, error) {
+	if len(buf) != ScalarSize {
+		return nil, fmt.Errorf("%w: scalar of %d bytes", errEncoding, len(buf))
+	}
+	x := s.suite.G1().Scalar()
+	if err := x.UnmarshalBinary(buf); err != nil {
+		return nil, fmt.Errorf("%w: %v", errEncoding, err)
+	}
+	return x, nil
+}
+
+// bytesToPoint decodes a commitment or a proof, a point of G1 which may be the point at infinity.
+func (s *Setup) bytesToPoint(buf []byte) (kyber.Point, error) {
+	if len(buf) != PointSize {
+		return nil, fmt.Errorf("%w: point of %d bytes", errEncoding, len(buf))
+	}
+	p := s.suite.G1().Point()
+	if err := p.UnmarshalBinary(buf); err != nil {
+		return nil, fmt.Errorf("%w: %v", errEncoding, err)
+	}
+	return p, nil
+}
+
+// challenge returns the Fiat-Shamir evaluation point of the blob and its commitment, as compute_challenge.
+func (s *Setup) challenge(blob, commitment []byte) kyber.Scalar {
+	var data bytes.Buffer
+	data.WriteString(blobDomain)
+	// the degree is written on 16 bytes
+	data.Write(make([]byte, 8))
+	_ = binary.Write(&data, binary.BigEndian, uint64(len(s.lagrangeBRP)))
+	data.Write(blob)
+	data.Write(commitment)
+	return hashToScalar(s.suite.G1(), data.Bytes())
+}
+
+// hashToScalar returns SHA-256(data) reduced modulo the group order, as hash_to_bls_field.
+func hashToScalar(g kyber.Group, data []byte) kyber.Scalar {
+	h := sha256.Sum256(data)
+	x := new(big.Int).SetBytes(h[:])
+	return g.Scalar().SetBytes(x.Mod(x, g.Scalar().(*mod.Int).M).Bytes())
+}
+
+// evaluate returns the value at z of the polynomial with the evaluations poly at the roots of unity in
+// bit-reversed order, with the barycentric formula, as evaluate_polynomial_in_evaluation_form.
+func (s *Setup) evaluate(poly []kyber.Scalar, z kyber.Scalar) kyber.Scalar {
+	g := s.suite.G1()
+	for i, root := range s.roots {
+		if root.Equal(z) {
+			return poly[i].Clone()
+		}
+	}
+	// (z^n - 1) / n * Σ poly_i ω_i / (z - ω_i)
+	n := len(poly)
+	sum := g.Scalar().Zero()
+	for i, root := range s.roots {
+		term := g.Scalar().Mul(poly[i], root)
+		sum.Add(sum, term.Div(term, g.Scalar().Sub(z, root)))
+	}
+	zn := g.Scalar().One()
+	for i := 0; i < n; i++ {
+		zn.Mul(zn, z)
+	}
+	zn.Sub(zn, g.Scalar().One())
+	sum.Mul(sum, zn)
+	return sum.Div(sum, g.Scalar().SetInt64(int64(n)))
+}
+
+// computeProof returns the proof that the polynomial with the evaluations poly is y at z, along with y, as
+// compute_kzg_proof_impl.
+func (s *Setup) computeProof(poly []kyber.Scalar, z kyber.Scalar) (kyber.Point, kyber.Scalar) {
+	g := s.suite.G1()
+	y := s.evaluate(poly, z)
+	// the evaluations of the quotient (f(X) - y) / (X - z) at the roots of unity
+	q := make([]kyber.Scalar, len(poly))
+	for i, root := range s.roots {
+		if root.Equal(z) {
+			q[i] = s.quotientAtRoot(poly, i, y)
+			continue
+		}
+		q[i] = g.Scalar().Sub(poly[i], y)
+		q[i].Div(q[i], g.Scalar().Sub(root, z))
+	}
+	return bls.LinearCombination(g.Point(), s.lagrangeBRP, q), y
+}
+
+// quotientAtRoot returns the value of (f(X) - y) / (X - z) at z, the root of unity of index m, as
+// compute_quotient_eval_within_domain.
+func (s *Setup) quotientAtRoot(poly []kyber.Scalar, m int, y kyber.Scalar) kyber.Scalar {
+	g := s.suite.G1()
+	z := s.roots[m]
+	result := g.Scalar().Zero()
+	for i, root := range s.roots {
+		if i == m {
+			continue
+		}
+		// (f_i - y) ω_i / (z (z - ω_i))
+		num := g.Scalar().Sub(poly[i], y)
+		num.Mul(num, root)
+		den := g.Scalar().Sub(z, root)
+		den.Mul(den, z)
+		result.Add(result, num.Div(num, den))
+	}
+	return result
+}
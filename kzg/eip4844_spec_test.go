@@ -0,0 +1,29 @@
+//go:build kzgspec
+
+package kzg
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestConsensusSpecSuite runs the full kzg-mainnet tests of the Ethereum consensus specification, which are
+// not checked in, from the tests directory given by KZG_SPEC_TESTS, e.g. the one of the go-eth-kzg module:
+//
+//	go mod download github.com/crate-crypto/go-eth-kzg@v1.5.0
+//	KZG_SPEC_TESTS=$(go env GOMODCACHE)/github.com/crate-crypto/go-eth-kzg@v1.5.0/tests \
+//		go test -tags kzgspec -run TestConsensusSpecSuite
+//
+// The functions of the suite which are not implemented, such as the ones of the cells of EIP-7594, are skipped.
+func TestConsensusSpecSuite(t *testing.T) {
+	dir := os.Getenv("KZG_SPEC_TESTS")
+	require.NotEmpty(t, dir, "KZG_SPEC_TESTS must point to the tests directory of the consensus specification")
+	for function := range specChecks {
+		t.Run(function, func(t *testing.T) {
+			require.NotZero(t, specTests(t, function, filepath.Join(dir, function, "kzg-mainnet")))
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package kzg
+
+import (
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	bls "github.com/drand/kyber-bls12381"
+	"github.com/drand/kyber/util/random"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+// The vectors under testdata are a subset of the kzg-mainnet tests of the Ethereum consensus specification,
+// with the trusted setup of EIP-4844, see testdata/README.md.
+
+var (
+	ethereumSetupOnce sync.Once
+	ethereumSetupErr  error
+	ethereumSetupVal  *Setup
+)
+
+func loadEthereumSetup(t *testing.T) *Setup {
+	ethereumSetupOnce.Do(func() {
+		f, err := os.Open("testdata/trusted_setup_4096.json")
+		if err != nil {
+			ethereumSetupErr = err
+			return
+		}
+		defer f.Close()
+		ethereumSetupVal, ethereumSetupErr = ReadEthereumSetup(bls.NewBLS12381Suite(), f)
+	})
+	require.NoError(t, ethereumSetupErr)
+	return ethereumSetupVal
+}
+
+// specTests checks the vectors of the function under dir, one directory per case, against specChecks.
+func specTests(t *testing.T, function, dir string) int {
+	s := loadEthereumSetup(t)
+	check := specChecks[function]
+	files, err := filepath.Glob(filepath.Join(dir, "*", "data.yaml"))
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+	for _, file := range files {
+		t.Run(filepath.Base(filepath.Dir(file)), func(t *testing.T) {
+			data, err := os.ReadFile(file)
+			require.NoError(t, err)
+			var vector struct {
+				Input  map[string]interface{} `yaml:"input"`
+				Output interface{}            `yaml:"output"`
+			}
+			require.NoError(t, yaml.Unmarshal(data, &vector))
+			check(t, s, vector.Input, vector.Output)
+		})
+	}
+	return len(files)
+}
+
+// specBytes decodes the hexadecimal strings of the vectors. Malformed strings are kept as they are, to be
+// rejected.
+func specBytes(v interface{}) []byte {
+	s, _ := v.(string)
+	buf, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return []byte(s)
+	}
+	return buf
+}
+
+func specBytesList(v interface{}) [][]byte {
+	list, _ := v.([]interface{})
+	out := make([][]byte, len(list))
+	for i, e := range list {
+		out[i] = specBytes(e)
+	}
+	return out
+}
+
+// specVerify checks the error of a verification against the expected output: true, false or nil.
+func specVerify(t *testing.T, err error, output interface{}) {
+	switch output {
+	case true:
+		require.NoError(t, err)
+	case false:
+		require.ErrorIs(t, err, ErrInvalidProof)
+	default:
+		require.Error(t, err)
+		require.NotErrorIs(t, err, ErrInvalidProof)
+	}
+}
+
+// specChecks calls each function with the input of a vector and checks its output, an output of nil meaning
+// that the function must fail.
+var specChecks = map[string]func(t *testing.T, s *Setup, input map[string]interface{}, output interface{}){
+	"blob_to_kzg_commitment": func(t *testing.T, s *Setup, input map[string]interface{}, output interface{}) {
+		c, err := s.BlobToKZGCommitment(specBytes(input["blob"]))
+		if output == nil {
+			require.Error(t, err)
+			return
+		}
+		require.NoError(t, err)
+		require.Equal(t, specBytes(output), c)
+	},
+	"compute_kzg_proof": func(t *testing.T, s *Setup, input map[string]interface{}, output interface{}) {
+		proof, y, err := s.ComputeKZGProof(specBytes(input["blob"]), specBytes(input["z"]))
+		if output == nil {
+			require.Error(t, err)
+			return
+		}
+		require.NoError(t, err)
+		require.Equal(t, specBytesList(output), [][]byte{proof, y})
+	},
+	"compute_blob_kzg_proof": func(t *testing.T, s *Setup, input map[string]interface{}, output interface{}) {
+		proof, err := s.ComputeBlobKZGProof(specBytes(input["blob"]), specBytes(input["commitment"]))
+		if output == nil {
+			require.Error(t, err)
+			return
+		}
+		require.NoError(t, err)
+		require.Equal(t, specBytes(output), proof)
+	},
+	"verify_kzg_proof": func(t *testing.T, s *Setup, input map[string]interface{}, output interface{}) {
+		err := s.VerifyKZGProof(specBytes(input["commitment"]), specBytes(input["z"]), specBytes(input["y"]),
+			specBytes(input["proof"]))
+		specVerify(t, err, output)
+	},
+	"verify_blob_kzg_proof_batch": func(t *testing.T, s *Setup, input map[string]interface{}, output interface{}) {
+		err := s.VerifyBlobKZGProofBatch(specBytesList(input["blobs"]), specBytesList(input["commitments"]),
+			specBytesList(input["proofs"]))
+		specVerify(t, err, output)
+	},
+	// verify_blob_kzg_proof, only run by TestConsensusSpecSuite, is a batch of one blob
+	"verify_blob_kzg_proof": func(t *testing.T, s *Setup, input map[string]interface{}, output interface{}) {
+		err := s.VerifyBlobKZGProofBatch([][]byte{specBytes(input["blob"])}, [][]byte{specBytes(input["commitment"])},
+			[][]byte{specBytes(input["proof"])})
+		specVerify(t, err, output)
+	},
+}
+
+func TestBlobToKZGCommitment(t *testing.T) {
+	specTests(t, "blob_to_kzg_commitment", filepath.Join("testdata", "blob_to_kzg_commitment"))
+}
+
+func TestComputeKZGProof(t *testing.T) {
+	specTests(t, "compute_kzg_proof", filepath.Join("testdata", "compute_kzg_proof"))
+}
+
+func TestComputeBlobKZGProof(t *testing.T) {
+	specTests(t, "compute_blob_kzg_proof", filepath.Join("testdata", "compute_blob_kzg_proof"))
+}
+
+func TestVerifyKZGProof(t *testing.T) {
+	specTests(t, "verify_kzg_proof", filepath.Join("testdata", "verify_kzg_proof"))
+}
+
+func TestVerifyBlobKZGProofBatch(t *testing.T) {
+	specTests(t, "verify_blob_kzg_proof_batch", filepath.Join("testdata", "verify_blob_kzg_proof_batch"))
+}
+
+func TestBlobRoundTrip(t *testing.T) {
+	suite := bls.NewBLS12381Suite()
+	g := suite.G1()
+	s := testSetup(t, suite, g.Scalar().Pick(random.New()), 0, 2, 8)
+	var blobs, commitments, proofs [][]byte
+	for i := 0; i < 3; i++ {
+		var blob []byte
+		for _, x := range randomPoly(g, 8) {
+			buf, err := x.MarshalBinary()
+			require.NoError(t, err)
+			blob = append(blob, buf...)
+		}
+		c, err := s.BlobToKZGCommitment(blob)
+		require.NoError(t, err)
+		proof, err := s.ComputeBlobKZGProof(blob, c)
+		require.NoError(t, err)
+		blobs, commitments, proofs = append(blobs, blob), append(commitments, c), append(proofs, proof)
+
+		// at a root of unity, the evaluation is the element of the blob
+		for _, j := range []int{0, 5} {
+			z, err := s.roots[j].MarshalBinary()
+			require.NoError(t, err)
+			proof, y, err := s.ComputeKZGProof(blob, z)
+			require.NoError(t, err)
+			require.Equal(t, blob[j*ScalarSize:(j+1)*ScalarSize], y)
+			require.NoError(t, s.VerifyKZGProof(c, z, y, proof))
+		}
+		z, err := g.Scalar().Pick(random.New()).MarshalBinary()
+		require.NoError(t, err)
+		proof, y, err := s.ComputeKZGProof(blob, z)
+		require.NoError(t, err)
+		require.NoError(t, s.VerifyKZGProof(c, z, y, proof))
+		require.ErrorIs(t, s.VerifyKZGProof(c, y, z, proof), ErrInvalidProof)
+	}
+	require.NoError(t, s.VerifyBlobKZGProofBatch(blobs, commitments, proofs))
+	proofs[0], proofs[1] = proofs[1], proofs[0]
+	require.ErrorIs(t, s.VerifyBlobKZGProofBatch(blobs, commitments, proofs), ErrInvalidProof)
+	require.Error(t, s.VerifyBlobKZGProofBatch(blobs, commitments, proofs[:2]))
+	_, err := s.BlobToKZGCommitment(blobs[0][:64])
+	require.Error(t, err)
+}
@@ -0,0 +1,151 @@
+package kzg
+
+import (
+	"crypto/cipher"
+	"errors"
+
+	"github.com/drand/kyber"
+	bls "github.com/drand/kyber-bls12381"
+	"github.com/drand/kyber-bls12381/poly"
+)
+
+// ErrInvalidProof is returned when an opening proof does not verify.
+var ErrInvalidProof = errors.New("kzg: invalid opening proof")
+
+var errLength = errors.New("kzg: inputs of different lengths")
+
+// Commit returns the commitment to the polynomial with the coefficients poly, starting with the constant
+// term.
+func (s *Setup) Commit(poly []kyber.Scalar) (kyber.Point, error) {
+	if len(poly) > len(s.G1) {
+		return nil, errSetupTooBig
+	}
+	return bls.LinearCombination(s.suite.G1().Point(), s.G1[:len(poly)], poly), nil
+}
+
+// CommitLagrange returns the commitment to the polynomial with the evaluations evals at the roots of unity of
+// order len(G1Lagrange), in their natural order.
+func (s *Setup) CommitLagrange(evals []kyber.Scalar) (kyber.Point, error) {
+	if len(evals) != len(s.G1Lagrange) {
+		return nil, errSetupTooBig
+	}
+	return bls.LinearCombination(s.suite.G1().Point(), s.G1Lagrange, evals), nil
+}
+
+// Open evaluates the polynomial with the coefficients p at z, returning the evaluation y along with the
+// proof that the committed polynomial is y at z.
+func (s *Setup) Open(p []kyber.Scalar, z kyber.Scalar) (y kyber.Scalar, proof kyber.Point, err error) {
+	g := s.suite.G1()
+	y = poly.Evaluate(g, p, z)
+	// (f(X) - y) / (X - z), by synthetic division
+	q := make([]kyber.Scalar, 0, len(p))
+	if len(p) > 1 {
+		q = q[:len(p)-1]
+		q[len(q)-1] = p[len(p)-1].Clone()
+		for i := len(q) - 1; i > 0; i-- {
+			q[i-1] = g.Scalar().Mul(z, q[i])
+			q[i-1].Add(q[i-1], p[i])
+		}
+	}
+	proof, err = s.Commit(q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return y, proof, nil
+}
+
+// Verify checks the proof that the polynomial committed to in commitment is y at z, returning ErrInvalidProof
+// if it is not.
+func (s *Setup) Verify(commitment kyber.Point, z, y kyber.Scalar, proof kyber.Point) error {
+	g1, g2 := s.suite.G1(), s.suite.G2()
+	// e(C - [y]G1, G2) = e(proof, [τ - z]G2)
+	cy := g1.Point().Sub(commitment, g1.Point().Mul(y, nil))
+	tz := g2.Point().Sub(s.G2[1], g2.Point().Mul(z, nil))
+	if !s.suite.ValidatePairing(cy, s.G2[0], proof, tz) {
+		return ErrInvalidProof
+	}
+	return nil
+}
+
+// OpenMulti evaluates the polynomial with the coefficients p at the distinct points zs, returning the
+// evaluations ys along with a single proof for all of them.
+func (s *Setup) OpenMulti(p []kyber.Scalar, zs []kyber.Scalar) (ys []kyber.Scalar, proof kyber.Point, err error) {
+	g := s.suite.G1()
+	ys = make([]kyber.Scalar, len(zs))
+	for i, z := range zs {
+		ys[i] = poly.Evaluate(g, p, z)
+	}
+	// the quotient of f by Z(X) = (X - z_0)...(X - z_k) is the quotient of f - I, I interpolating the ys at
+	// the zs, as I has a lower degree than Z
+	q, _, err := poly.Div(g, p, poly.Vanishing(g, zs))
+	if err != nil {
+		return nil, nil, err
+	}
+	proof, err = s.Commit(q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ys, proof, nil
+}
+
+// VerifyMulti checks the proof that the polynomial committed to in commitment is ys[i] at zs[i] for all i,
+// returning ErrInvalidProof if it is not. The setup must hold len(zs) powers in G1 and len(zs)+1 in G2.
+func (s *Setup) VerifyMulti(commitment kyber.Point, zs, ys []kyber.Scalar, proof kyber.Point) error {
+	if len(zs) != len(ys) {
+		return errLength
+	}
+	if len(zs) >= len(s.G2) {
+		return errSetupTooBig
+	}
+	g1, g2 := s.suite.G1(), s.suite.G2()
+	interpolation, err := poly.Interpolate(g1, zs, ys)
+	if err != nil {
+		return err
+	}
+	ci, err := s.Commit(interpolation)
+	if err != nil {
+		return err
+	}
+	z := poly.Vanishing(g1, zs)
+	cz := bls.LinearCombination(g2.Point(), s.G2[:len(z)], z)
+	// e(C - [I(τ)]G1, G2) = e(proof, [Z(τ)]G2)
+	if !s.suite.ValidatePairing(g1.Point().Sub(commitment, ci), s.G2[0], proof, cz) {
+		return ErrInvalidProof
+	}
+	return nil
+}
+
+// VerifyBatch checks the proofs that the polynomials committed to in commitments[i] are ys[i] at zs[i],
+// returning ErrInvalidProof if one of them is not. The proofs are combined with random scalars from rand,
+// for a cost of two pairings.
+func (s *Setup) VerifyBatch(commitments []kyber.Point, zs, ys []kyber.Scalar, proofs []kyber.Point, rand cipher.Stream) error {
+	if len(commitments) != len(zs) || len(zs) != len(ys) || len(ys) != len(proofs) {
+		return errLength
+	}
+	return s.verifyBatch(commitments, zs, ys, proofs, s.suite.G1().Scalar().Pick(rand))
+}
+
+// verifyBatch checks the proofs, combined with the powers of r.
+func (s *Setup) verifyBatch(commitments []kyber.Point, zs, ys []kyber.Scalar, proofs []kyber.Point, r kyber.Scalar) error {
+	g1 := s.suite.G1()
+	powers := make([]kyber.Scalar, len(commitments))
+	zPowers := make([]kyber.Scalar, len(commitments))
+	cys := make([]kyber.Point, len(commitments))
+	for i := range commitments {
+		if i == 0 {
+			powers[i] = g1.Scalar().One()
+		} else {
+			powers[i] = g1.Scalar().Mul(powers[i-1], r)
+		}
+		zPowers[i] = g1.Scalar().Mul(zs[i], powers[i])
+		cys[i] = g1.Point().Sub(commitments[i], g1.Point().Mul(ys[i], nil))
+	}
+	// e(Σ r^i proof_i, [τ]G2) = e(Σ r^i (C_i - [y_i]G1) + Σ r^i z_i proof_i, G2)
+	proof := bls.LinearCombination(g1.Point(), proofs, powers)
+	rhs := g1.Point().Add(bls.LinearCombination(g1.Point(), cys, powers),
+		bls.LinearCombination(g1.Point(), proofs, zPowers))
+	if !s.suite.ValidatePairing(proof, s.G2[1], rhs, s.G2[0]) {
+		return ErrInvalidProof
+	}
+	return nil
+}
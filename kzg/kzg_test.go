@@ -0,0 +1,193 @@
+package kzg
+
+import (
+	"testing"
+
+	"github.com/drand/kyber"
+	bls "github.com/drand/kyber-bls12381"
+	"github.com/drand/kyber-bls12381/poly"
+	"github.com/drand/kyber/pairing"
+	"github.com/drand/kyber/util/random"
+	"github.com/stretchr/testify/require"
+)
+
+// testSetup returns a setup with n powers in G1, m in G2 and l Lagrange powers, from a known τ.
+func testSetup(t *testing.T, suite pairing.Suite, tau kyber.Scalar, n, m, l int) *Setup {
+	g1, g2 := suite.G1(), suite.G2()
+	powers := func(g kyber.Group, k int) []kyber.Point {
+		ps := make([]kyber.Point, k)
+		x := g.Scalar().One()
+		for i := range ps {
+			ps[i] = g.Point().Mul(x, nil)
+			x = g.Scalar().Mul(x, tau)
+		}
+		return ps
+	}
+	lagrange := make([]kyber.Point, l)
+	if l > 0 {
+		domain, err := poly.NewDomain(g1, l)
+		require.NoError(t, err)
+		// L_i(τ) = ω^i (τ^l - 1) / (l (τ - ω^i))
+		taul := g1.Scalar().One()
+		for i := 0; i < l; i++ {
+			taul.Mul(taul, tau)
+		}
+		taul.Sub(taul, g1.Scalar().One())
+		for i, root := range domain.Elements() {
+			li := g1.Scalar().Mul(root, taul)
+			li.Div(li, g1.Scalar().Mul(g1.Scalar().SetInt64(int64(l)), g1.Scalar().Sub(tau, root)))
+			lagrange[i] = g1.Point().Mul(li, nil)
+		}
+	}
+	s, err := NewSetup(suite, powers(g1, n), lagrange, powers(g2, m))
+	require.NoError(t, err)
+	return s
+}
+
+func randomPoly(g kyber.Group, n int) []kyber.Scalar {
+	p := make([]kyber.Scalar, n)
+	for i := range p {
+		p[i] = g.Scalar().Pick(random.New())
+	}
+	return p
+}
+
+func TestOpen(t *testing.T) {
+	suite := bls.NewBLS12381Suite()
+	g := suite.G1()
+	tau := g.Scalar().Pick(random.New())
+	s := testSetup(t, suite, tau, 16, 2, 0)
+	for _, n := range []int{0, 1, 2, 16} {
+		p := randomPoly(g, n)
+		c, err := s.Commit(p)
+		require.NoError(t, err)
+		// the commitment is [f(τ)]G1
+		require.True(t, c.Equal(g.Point().Mul(poly.Evaluate(g, p, tau), nil)))
+
+		z := g.Scalar().Pick(random.New())
+		y, proof, err := s.Open(p, z)
+		require.NoError(t, err)
+		require.True(t, y.Equal(poly.Evaluate(g, p, z)))
+		require.NoError(t, s.Verify(c, z, y, proof))
+
+		wrong := g.Scalar().Add(y, g.Scalar().One())
+		require.ErrorIs(t, s.Verify(c, z, wrong, proof), ErrInvalidProof)
+		if n > 1 {
+			// constant polynomials have the same value everywhere
+			require.ErrorIs(t, s.Verify(c, wrong, y, proof), ErrInvalidProof)
+		}
+	}
+	_, err := s.Commit(randomPoly(g, 17))
+	require.Error(t, err)
+}
+
+func TestOpenMulti(t *testing.T) {
+	suite := bls.NewBLS12381Suite()
+	g := suite.G1()
+	s := testSetup(t, suite, g.Scalar().Pick(random.New()), 16, 5, 0)
+	p := randomPoly(g, 10)
+	c, err := s.Commit(p)
+	require.NoError(t, err)
+	for _, k := range []int{1, 2, 4} {
+		zs := randomPoly(g, k)
+		ys, proof, err := s.OpenMulti(p, zs)
+		require.NoError(t, err)
+		for i := range zs {
+			require.True(t, ys[i].Equal(poly.Evaluate(g, p, zs[i])))
+		}
+		require.NoError(t, s.VerifyMulti(c, zs, ys, proof))
+
+		ys[k-1] = g.Scalar().Add(ys[k-1], g.Scalar().One())
+		require.ErrorIs(t, s.VerifyMulti(c, zs, ys, proof), ErrInvalidProof)
+	}
+	// a single point is a regular opening
+	z := g.Scalar().Pick(random.New())
+	y, proof, err := s.Open(p, z)
+	require.NoError(t, err)
+	require.NoError(t, s.VerifyMulti(c, []kyber.Scalar{z}, []kyber.Scalar{y}, proof))
+
+	zs := randomPoly(g, 5)
+	ys, proof, err := s.OpenMulti(p, zs)
+	require.NoError(t, err)
+	require.Error(t, s.VerifyMulti(c, zs, ys, proof), "not enough powers of G2")
+	require.Error(t, s.VerifyMulti(c, zs[:2], ys[:1], proof))
+	require.Error(t, s.VerifyMulti(c, []kyber.Scalar{z, z}, []kyber.Scalar{y, y}, proof), "duplicate points")
+}
+
+func TestVerifyBatch(t *testing.T) {
+	suite := bls.NewBLS12381Suite()
+	g := suite.G1()
+	s := testSetup(t, suite, g.Scalar().Pick(random.New()), 8, 2, 0)
+	var cs, proofs []kyber.Point
+	var zs, ys []kyber.Scalar
+	for i := 0; i < 5; i++ {
+		p := randomPoly(g, 8)
+		c, err := s.Commit(p)
+		require.NoError(t, err)
+		z := g.Scalar().Pick(random.New())
+		y, proof, err := s.Open(p, z)
+		require.NoError(t, err)
+		cs, zs, ys, proofs = append(cs, c), append(zs, z), append(ys, y), append(proofs, proof)
+	}
+	require.NoError(t, s.VerifyBatch(cs, zs, ys, proofs, random.New()))
+	require.NoError(t, s.VerifyBatch(nil, nil, nil, nil, random.New()))
+
+	// swapped proofs verify neither alone nor in batch
+	proofs[1], proofs[2] = proofs[2], proofs[1]
+	require.ErrorIs(t, s.VerifyBatch(cs, zs, ys, proofs, random.New()), ErrInvalidProof)
+	require.Error(t, s.VerifyBatch(cs, zs, ys, proofs[:4], random.New()))
+}
+
+func TestCommitLagrange(t *testing.T) {
+	suite := bls.NewBLS12381Suite()
+	g := suite.G1()
+	s := testSetup(t, suite, g.Scalar().Pick(random.New()), 8, 2, 8)
+	p := randomPoly(g, 8)
+	domain, err := poly.NewDomain(g, 8)
+	require.NoError(t, err)
+	roots := domain.Elements()
+	evals := make([]kyber.Scalar, len(roots))
+	for i, root := range roots {
+		evals[i] = poly.Evaluate(g, p, root)
+	}
+	c, err := s.Commit(p)
+	require.NoError(t, err)
+	cl, err := s.CommitLagrange(evals)
+	require.NoError(t, err)
+	require.True(t, c.Equal(cl))
+	_, err = s.CommitLagrange(evals[:4])
+	require.Error(t, err)
+}
+
+func TestNewSetupErrors(t *testing.T) {
+	suite := bls.NewBLS12381Suite()
+	g := suite.G1()
+	s := testSetup(t, suite, g.Scalar().Pick(random.New()), 4, 2, 4)
+	other := testSetup(t, suite, g.Scalar().Pick(random.New()), 4, 2, 4)
+
+	_, err := NewSetup(suite, s.G1, nil, other.G2)
+	require.Error(t, err, "different τ")
+	_, err = NewSetup(suite, nil, s.G1Lagrange, s.G2[:1])
+	require.Error(t, err, "one power of G2")
+	_, err = NewSetup(suite, s.G1[1:], nil, s.G2)
+	require.Error(t, err, "no generator")
+	_, err = NewSetup(suite, nil, s.G1Lagrange[:3], s.G2)
+	require.Error(t, err, "not a power of two")
+	_, err = NewSetup(suite, nil, s.G1[:4], s.G2)
+	require.Error(t, err, "not Lagrange powers")
+	_, err = NewSetup(suite, nil, nil, s.G2)
+	require.Error(t, err)
+
+	_, err = NewSetup(suite, nil, s.G1Lagrange, s.G2)
+	require.NoError(t, err)
+	_, err = NewSetup(suite, nil, other.G1Lagrange, s.G2)
+	require.Error(t, err, "Lagrange points of another τ")
+	_, err = NewSetup(suite, s.G1, other.G1Lagrange, s.G2)
+	require.Error(t, err, "Lagrange points of another τ than G1")
+	swapped := append([]kyber.Point{s.G1Lagrange[1], s.G1Lagrange[0]}, s.G1Lagrange[2:]...)
+	_, err = NewSetup(suite, nil, swapped, s.G2)
+	require.Error(t, err, "Lagrange points out of order")
+	g1 := append(append([]kyber.Point(nil), s.G1[:3]...), other.G1[3])
+	_, err = NewSetup(suite, g1, nil, s.G2)
+	require.Error(t, err, "a power of another τ")
+}
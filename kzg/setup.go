@@ -0,0 +1,170 @@
+// Package kzg implements KZG polynomial commitments over BLS12-381, along with the blob commitments of
+// EIP-4844.
+package kzg
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/drand/kyber"
+	bls "github.com/drand/kyber-bls12381"
+	"github.com/drand/kyber-bls12381/poly"
+	"github.com/drand/kyber/pairing"
+)
+
+var (
+	errSetup       = errors.New("kzg: invalid trusted setup")
+	errSetupTooBig = errors.New("kzg: not enough powers in the trusted setup")
+)
+
+// Setup is a trusted setup, holding the powers of a secret τ in G1 and G2. The polynomials are over the
+// scalar field of the suite.
+type Setup struct {
+	suite pairing.Suite
+	// G1 holds [τ^i]G1, to commit to polynomials given by their coefficients. It may be empty when
+	// G1Lagrange is set.
+	G1 []kyber.Point
+	// G1Lagrange holds [L_i(τ)]G1 for the Lagrange polynomials L_i over the roots of unity of order
+	// len(G1Lagrange), in their natural order, to commit to polynomials given by their evaluations. It may be
+	// empty.
+	G1Lagrange []kyber.Point
+	// G2 holds [τ^i]G2, of which at least [1]G2 and [τ]G2.
+	G2 []kyber.Point
+
+	// lagrangeBRP is G1Lagrange in bit-reversed order, and roots the roots of unity in that order.
+	lagrangeBRP []kyber.Point
+	roots       []kyber.Scalar
+}
+
+// NewSetup returns the trusted setup with the given powers of τ, see Setup. It checks that the first powers
+// are the generators, and that the powers of G1 and the Lagrange points are those of the τ of G2[1], with
+// random linear combinations.
+func NewSetup(suite pairing.Suite, g1, g1Lagrange, g2 []kyber.Point) (*Setup, error) {
+	s := &Setup{suite: suite, G1: g1, G1Lagrange: g1Lagrange, G2: g2}
+	if len(g2) < 2 || len(g1) == 0 && len(g1Lagrange) == 0 {
+		return nil, fmt.Errorf("%w: missing powers", errSetup)
+	}
+	if !g2[0].Equal(suite.G2().Point().Base()) {
+		return nil, fmt.Errorf("%w: the first power of G2 is not the generator", errSetup)
+	}
+	rand := suite.RandomStream()
+	if len(g1) > 0 {
+		if !g1[0].Equal(suite.G1().Point().Base()) {
+			return nil, fmt.Errorf("%w: the first power of G1 is not the generator", errSetup)
+		}
+		// Σ r_i [τ^(i+1)]G1 = τ Σ r_i [τ^i]G1
+		r := make([]kyber.Scalar, len(g1)-1)
+		for i := range r {
+			r[i] = suite.G1().Scalar().Pick(rand)
+		}
+		next := bls.LinearCombination(suite.G1().Point(), g1[1:], r)
+		cur := bls.LinearCombination(suite.G1().Point(), g1[:len(g1)-1], r)
+		if !suite.ValidatePairing(next, g2[0], cur, g2[1]) {
+			return nil, fmt.Errorf("%w: the powers of G1 and G2 do not match", errSetup)
+		}
+	}
+	if n := len(g1Lagrange); n > 0 {
+		domain, err := poly.NewDomain(suite.G1(), n)
+		if err != nil {
+			return nil, err
+		}
+		// the sum of the Lagrange polynomials is 1
+		sum := suite.G1().Point().Null()
+		for _, p := range g1Lagrange {
+			sum.Add(sum, p)
+		}
+		if !sum.Equal(suite.G1().Point().Base()) {
+			return nil, fmt.Errorf("%w: the Lagrange powers do not sum to the generator", errSetup)
+		}
+		// X^j = Σ ω^(ij) L_i(X) for j < n, so for a random polynomial R of degree n - 2, Σ R(ω^i) L_i(τ) is
+		// Σ r_j τ^j and Σ ω^i R(ω^i) L_i(τ) is τ times it
+		r := make([]kyber.Scalar, n-1)
+		for i := range r {
+			r[i] = suite.G1().Scalar().Pick(rand)
+		}
+		evals, err := domain.NTT(r)
+		if err != nil {
+			return nil, err
+		}
+		shifted := make([]kyber.Scalar, n)
+		for i, root := range domain.Elements() {
+			shifted[i] = suite.G1().Scalar().Mul(root, evals[i])
+		}
+		next := bls.LinearCombination(suite.G1().Point(), g1Lagrange, shifted)
+		cur := bls.LinearCombination(suite.G1().Point(), g1Lagrange, evals)
+		if !suite.ValidatePairing(next, g2[0], cur, g2[1]) {
+			return nil, fmt.Errorf("%w: the Lagrange powers and G2 do not match", errSetup)
+		}
+		s.roots = poly.BitReverse(domain.Elements())
+		s.lagrangeBRP = poly.BitReverse(g1Lagrange)
+	}
+	return s, nil
+}
+
+// ethereumSetup is the JSON trusted setup of the Ethereum consensus specification, e.g. trusted_setup_4096.json
+// for EIP-4844, with compressed points in hexadecimal.
+type ethereumSetup struct {
+	G1Monomial []string `json:"g1_monomial"`
+	G1Lagrange []string `json:"g1_lagrange"`
+	G2Monomial []string `json:"g2_monomial"`
+}
+
+// ReadEthereumSetup reads a trusted setup in the JSON format of the Ethereum consensus specification, whose
+// g1_monomial, g1_lagrange and g2_monomial hold G1, G1Lagrange and G2. The older files without g1_monomial
+// are accepted, only allowing commitments from evaluations.
+func ReadEthereumSetup(suite pairing.Suite, r io.Reader) (*Setup, error) {
+	var setup ethereumSetup
+	if err := json.NewDecoder(r).Decode(&setup); err != nil {
+		return nil, fmt.Errorf("%w: %v", errSetup, err)
+	}
+	g1, err := decodePoints(suite.G1(), setup.G1Monomial)
+	if err != nil {
+		return nil, err
+	}
+	g1Lagrange, err := decodePoints(suite.G1(), setup.G1Lagrange)
+	if err != nil {
+		return nil, err
+	}
+	g2, err := decodePoints(suite.G2(), setup.G2Monomial)
+	if err != nil {
+		return nil, err
+	}
+	return NewSetup(suite, g1, g1Lagrange, g2)
+}
+
+// decodePoints decodes the hexadecimal encodings of points of g in parallel, as the subgroup checks of the
+// thousands of points of a setup take seconds.
+func decodePoints(g kyber.Group, encodings []string) ([]kyber.Point, error) {
+	points := make([]kyber.Point, len(encodings))
+	errs := make([]error, len(encodings))
+	var wg sync.WaitGroup
+	workers := runtime.GOMAXPROCS(0)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := w; i < len(encodings); i += workers {
+				buf, err := hex.DecodeString(strings.TrimPrefix(encodings[i], "0x"))
+				if err != nil {
+					errs[i] = err
+					continue
+				}
+				points[i] = g.Point()
+				errs[i] = points[i].UnmarshalBinary(buf)
+			}
+		}(w)
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("%w: point %d: %v", errSetup, i, err)
+		}
+	}
+	return points, nil
+}
@@ -0,0 +1,91 @@
+package bls
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func affineG1(t *testing.T, x, y string) *KyberG1 {
+	p, err := defaultBackend.g1().fromUncompressed(append(fpToBytes(fpFromHex(x)), fpToBytes(fpFromHex(y))...))
+	require.NoError(t, err)
+	return newKyberG1(p, nil, nil)
+}
+
+func affineG2(t *testing.T, x0, x1, y0, y1 string) *KyberG2 {
+	buf := append(fp2FromHex(x0, x1).Bytes(), fp2FromHex(y0, y1).Bytes()...)
+	p, err := defaultBackend.g2().fromUncompressed(buf)
+	require.NoError(t, err)
+	return newKyberG2(p, nil, nil)
+}
+
+// intermediate values of the msg = "abc" vectors from RFC 9380 appendix J.9.1 and J.10.1
+func TestMapToCurveG1(t *testing.T) {
+	u0 := fpFromHex("0d921c33f2bad966478a03ca35d05719bdf92d347557ea166e5bba579eea9b83e9afa5c088573c2281410369fbd32951")
+	u1 := fpFromHex("003574a00b109ada2f26a37a91f9d1e740dffd8d69ec0c35e1e9f4652c7dba61123e9dd2e76c655d956e2b3462611139")
+
+	// the SWU output must lie on E1'
+	x, y := MapToCurveSSWUG1(u0)
+	rhs := fpAdd(fpMul(fpAdd(fpMul(x, x), sswuG1.a), x), sswuG1.b)
+	require.Equal(t, 0, fpMul(y, y).Cmp(rhs))
+
+	q0, err := MapToCurveG1(u0)
+	require.NoError(t, err)
+	require.True(t, q0.Equal(affineG1(t,
+		"125435adce8e1cbd1c803e7123f45392dc6e326d292499c2c45c5865985fd74fe8f042ecdeeec5ecac80680d04317d80",
+		"0e8828948c989126595ee30e4f7c931cbd6f4570735624fd25aef2fa41d3f79cfb4b4ee7b7e55a8ce013af2a5ba20bf2")))
+	q1, err := MapToCurveG1(u1)
+	require.NoError(t, err)
+	require.True(t, q1.Equal(affineG1(t,
+		"11def93719829ecda3b46aa8c31fc3ac9c34b428982b898369608e4f042babee6c77ab9218aad5c87ba785481eff8ae4",
+		"0007c9cef122ccf2efd233d6eb9bfc680aa276652b0661f4f820a653cec1db7ff69899f8e52b8e92b025a12c822a6ce6")))
+	require.False(t, q0.IsInCorrectGroup())
+
+	p := NullKyberG1().ClearCofactor(q0.Add(q0, q1))
+	require.True(t, p.(*KyberG1).IsInCorrectGroup())
+	require.True(t, p.Equal(affineG1(t,
+		"03567bc5ef9c690c2ab2ecdf6a96ef1c139cc0b2f284dca0a9a7943388a49a3aee664ba5379a7655d3c68900be2f6903",
+		"0b9c15f3fe6e5cf4211f346271d7b01c8f3b28be689c8429c85b67af215533311f0b8dfaaa154fa6b88176c229f2885d")))
+
+	// the composition of the stages is the hash to curve
+	h, _ := NullKyberG1([]byte("QUUX-V01-CS02-with-BLS12381G1_XMD:SHA-256_SSWU_RO_")...).Hash([]byte("abc")).MarshalBinary()
+	b, _ := p.MarshalBinary()
+	require.Equal(t, h, b)
+}
+
+func TestMapToCurveG2(t *testing.T) {
+	u0 := fp2FromHex(
+		"15f7c0aa8f6b296ab5ff9c2c7581ade64f4ee6f1bf18f55179ff44a2cf355fa53dd2a2158c5ecb17d7c52f63e7195771",
+		"01c8067bf4c0ba709aa8b9abc3d1cef589a4758e09ef53732d670fd8739a7274e111ba2fcaa71b3d33df2a3a0c8529dd")
+	u1 := fp2FromHex(
+		"187111d5e088b6b9acfdfad078c4dacf72dcd17ca17c82be35e79f8c372a693f60a033b461d81b025864a0ad051a06e4",
+		"08b852331c96ed983e497ebc6dee9b75e373d923b729194af8e72a051ea586f3538a6ebb1e80881a082fa2b24df9f566")
+
+	// the SWU output must lie on E2'
+	x, y := MapToCurveSSWUG2(u0)
+	rhs := fp2Add(fp2Mul(fp2Add(fp2Square(x), sswuG2.a), x), sswuG2.b)
+	require.True(t, fp2Square(y).equal(rhs))
+
+	q0, err := MapToCurveG2(u0)
+	require.NoError(t, err)
+	require.True(t, q0.Equal(affineG2(t,
+		"12b2e525281b5f4d2276954e84ac4f42cf4e13b6ac4228624e17760faf94ce5706d53f0ca1952f1c5ef75239aeed55ad",
+		"05d8a724db78e570e34100c0bc4a5fa84ad5839359b40398151f37cff5a51de945c563463c9efbdda569850ee5a53e77",
+		"02eacdc556d0bdb5d18d22f23dcb086dd106cad713777c7e6407943edbe0b3d1efe391eedf11e977fac55f9b94f2489c",
+		"04bbe48bfd5814648d0b9e30f0717b34015d45a861425fabc1ee06fdfce36384ae2c808185e693ae97dcde118f34de41")))
+	q1, err := MapToCurveG2(u1)
+	require.NoError(t, err)
+	require.True(t, q1.Equal(affineG2(t,
+		"19f18cc5ec0c2f055e47c802acc3b0e40c337256a208001dde14b25afced146f37ea3d3ce16834c78175b3ed61f3c537",
+		"15b0dadc256a258b4c68ea43605dffa6d312eef215c19e6474b3e101d33b661dfee43b51abbf96fee68fc6043ac56a58",
+		"05e47c1781286e61c7ade887512bd9c2cb9f640d3be9cf87ea0bad24bd0ebfe946497b48a581ab6c7d4ca74b5147287f",
+		"19f98db2f4a1fcdf56a9ced7b320ea9deecf57c8e59236b0dc21f6ee7229aa9705ce9ac7fe7a31c72edca0d92370c096")))
+
+	p := NullKyberG2().ClearCofactor(q0.Add(q0, q1))
+	require.True(t, p.(*KyberG2).IsInCorrectGroup())
+	require.True(t, p.Equal(affineG2(t,
+		"02c2d18e033b960562aae3cab37a27ce00d80ccd5ba4b7fe0e7a210245129dbec7780ccc7954725f4168aff2787776e6",
+		"139cddbccdc5e91b9623efd38c49f81a6f83f175e80b06fc374de9eb4b41dfe4ca3a230ed250fbe3a2acf73a41177fd8",
+		"1787327b68159716a37440985269cf584bcb1e621d3a7202be6ea05c4cfe244aeb197642555a0645fb87bf7466b2ba48",
+		"00aa65dae3c8d732d10ecd2c50f8a1baf3001578f71c694e03866e9f3d49ac1e1ce70dd94a733534f106d4cec0eddd16")))
+}
@@ -0,0 +1,78 @@
+package bls
+
+import (
+	"math/big"
+
+	"github.com/drand/kyber"
+	"github.com/drand/kyber/group/mod"
+)
+
+// MultiExp sets k to the sum of the points ps multiplied by the scalars ss, computed with the bucket method
+// of the backend, much faster than with Mul and Add. It panics if ps and ss have different lengths, as well
+// as on points of another group or domain mismatches, as Add.
+func (k *KyberG1) MultiExp(ps []kyber.Point, ss []kyber.Scalar) kyber.Point {
+	if len(ps) != len(ss) {
+		panic("bls12-381: MultiExp with different numbers of points and scalars")
+	}
+	k.setIfEmpty()
+	points := make([]point, len(ps))
+	var outside bool
+	for i, p := range ps {
+		pp := p.(*KyberG1)
+		k.mix(pp)
+		points[i] = k.in(pp)
+		outside = outside || pp.outside
+	}
+	k.p.set(k.backend.g1().multiExp(points, multiExpScalars(ss)))
+	k.outside = outside
+	return k
+}
+
+// MultiExp sets k to the sum of the points ps multiplied by the scalars ss, computed with the bucket method
+// of the backend, much faster than with Mul and Add. It panics if ps and ss have different lengths, as well
+// as on points of another group or domain mismatches, as Add.
+func (k *KyberG2) MultiExp(ps []kyber.Point, ss []kyber.Scalar) kyber.Point {
+	if len(ps) != len(ss) {
+		panic("bls12-381: MultiExp with different numbers of points and scalars")
+	}
+	k.setIfEmpty()
+	points := make([]point, len(ps))
+	var outside bool
+	for i, p := range ps {
+		pp := p.(*KyberG2)
+		k.mix(pp)
+		points[i] = k.in(pp)
+		outside = outside || pp.outside
+	}
+	k.p.set(k.backend.g2().multiExp(points, multiExpScalars(ss)))
+	k.outside = outside
+	return k
+}
+
+// LinearCombination sets r to the sum of the points ps, of the group of r, multiplied by the scalars ss and
+// returns r. It computes the sum with MultiExp for the points of G1 and G2, and with Mul and Add otherwise,
+// e.g. for GT or the points of another implementation of kyber. It panics if ps and ss have different
+// lengths.
+func LinearCombination(r kyber.Point, ps []kyber.Point, ss []kyber.Scalar) kyber.Point {
+	if len(ps) != len(ss) {
+		panic("bls12-381: LinearCombination with different numbers of points and scalars")
+	}
+	if m, ok := r.(interface {
+		MultiExp(ps []kyber.Point, ss []kyber.Scalar) kyber.Point
+	}); ok {
+		return m.MultiExp(ps, ss)
+	}
+	sum, t := r.Clone().Null(), r.Clone()
+	for i := range ps {
+		sum.Add(sum, t.Mul(ss[i], ps[i]))
+	}
+	return r.Set(sum)
+}
+
+func multiExpScalars(ss []kyber.Scalar) []*big.Int {
+	scalars := make([]*big.Int, len(ss))
+	for i, s := range ss {
+		scalars[i] = &s.(*mod.Int).V
+	}
+	return scalars
+}
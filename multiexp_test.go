@@ -0,0 +1,74 @@
+package bls
+
+import (
+	"testing"
+
+	"github.com/drand/kyber"
+	"github.com/drand/kyber/util/random"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiExp(t *testing.T) {
+	type multiExp interface {
+		MultiExp(ps []kyber.Point, ss []kyber.Scalar) kyber.Point
+	}
+	for _, b := range backends {
+		suite := NewBLS12381Suite(WithBackend(b))
+		for _, g := range []kyber.Group{suite.G1(), suite.G2()} {
+			t.Run(b.String()+"/"+g.String(), func(t *testing.T) {
+				for _, n := range []int{0, 1, 2, 40} {
+					ps := make([]kyber.Point, n)
+					ss := make([]kyber.Scalar, n)
+					want := g.Point().Null()
+					for i := range ps {
+						ps[i] = g.Point().Pick(random.New())
+						ss[i] = g.Scalar().Pick(random.New())
+						switch i {
+						case 0:
+							ss[i].Zero()
+						case 1:
+							ps[i].Null()
+						case 2:
+							ss[i].SetInt64(-1)
+						}
+						want.Add(want, g.Point().Mul(ss[i], ps[i]))
+					}
+					got := g.Point().(multiExp).MultiExp(ps, ss)
+					require.True(t, got.Equal(want), "%d points", n)
+				}
+				require.Panics(t, func() {
+					g.Point().(multiExp).MultiExp([]kyber.Point{g.Point().Base()}, nil)
+				})
+			})
+		}
+	}
+}
+
+// LinearCombination must give the same sums with MultiExp and with Mul and Add, which it uses for GT.
+func TestLinearCombination(t *testing.T) {
+	suite := NewBLS12381Suite()
+	for _, g := range []kyber.Group{suite.G1(), suite.G2(), suite.GT()} {
+		t.Run(g.String(), func(t *testing.T) {
+			ps := make([]kyber.Point, 3)
+			ss := make([]kyber.Scalar, 3)
+			want := g.Point().Null()
+			for i := range ps {
+				if _, ok := g.Point().(*KyberGT); ok {
+					// GT has no base point to pick from
+					ps[i] = suite.Pair(suite.G1().Point().Pick(random.New()), suite.G2().Point().Base())
+				} else {
+					ps[i] = g.Point().Pick(random.New())
+				}
+				ss[i] = g.Scalar().Pick(random.New())
+				want.Add(want, g.Point().Mul(ss[i], ps[i]))
+			}
+			require.True(t, LinearCombination(g.Point(), ps, ss).Equal(want))
+			require.True(t, LinearCombination(g.Point(), nil, nil).Equal(g.Point().Null()))
+			// the result may be one of the points
+			r := ps[0]
+			require.True(t, LinearCombination(r, ps, ss).Equal(want))
+			require.True(t, r.Equal(want))
+			require.Panics(t, func() { LinearCombination(g.Point(), ps, ss[:2]) })
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package bls
+
+import (
+	"testing"
+
+	"github.com/drand/kyber/sign/bls"
+	"github.com/stretchr/testify/require"
+)
+
+// The negative corpus under testdata/negative lists malformed encodings and invalid signatures, each with
+// flags giving the reason it must be rejected. The flags are described in the notes of each file.
+
+type negativeCorpus struct {
+	Algorithm string            `json:"algorithm"`
+	Notes     map[string]string `json:"notes"`
+	Tests     []struct {
+		TcID     int      `json:"tcId"`
+		Comment  string   `json:"comment"`
+		Encoding string   `json:"encoding"`
+		Point    string   `json:"point"`
+		Result   string   `json:"result"`
+		Flags    []string `json:"flags"`
+	} `json:"tests"`
+}
+
+// decodingErrors are the errors returned for the encodings with each flag of the corpus.
+var decodingErrors = map[string]error{
+	"WrongLength":            ErrEncodingLength,
+	"MissingCompressionFlag": ErrCompressionFlag,
+	"InfinityNonZero":        ErrInfinityEncoding,
+	"InfinitySortFlag":       ErrInfinityEncoding,
+	"NonCanonicalX":          ErrNonCanonical,
+	"NonCanonical":           ErrNonCanonical,
+	"NotOnCurve":             ErrNotOnCurve,
+	"NotInSubgroup":          ErrNotInSubgroup,
+}
+
+func testNegativeDecoding(t *testing.T, file string, newPoint func() GroupChecker, fromRaw func([]byte) GroupChecker) {
+	var corpus negativeCorpus
+	loadJSON(t, "testdata/negative/"+file, &corpus)
+	require.NotEmpty(t, corpus.Tests)
+	for _, tc := range corpus.Tests {
+		for _, flag := range tc.Flags {
+			require.Contains(t, corpus.Notes, flag, "tcId %d", tc.TcID)
+		}
+		enc := decodeHex(t, tc.Encoding)
+		p := newPoint()
+		err := p.UnmarshalBinary(enc)
+		switch tc.Result {
+		case "valid":
+			require.NoError(t, err, "tcId %d: %s", tc.TcID, tc.Comment)
+			require.True(t, p.IsInCorrectGroup(), "tcId %d: %s", tc.TcID, tc.Comment)
+			buf, err := p.MarshalBinary()
+			require.NoError(t, err)
+			require.Equal(t, enc, buf, "tcId %d: %s", tc.TcID, tc.Comment)
+		case "invalid":
+			// the encoding is rejected for the reason given by its flag
+			require.Len(t, tc.Flags, 1, "tcId %d", tc.TcID)
+			require.Contains(t, decodingErrors, tc.Flags[0], "tcId %d", tc.TcID)
+			require.ErrorIs(t, err, decodingErrors[tc.Flags[0]], "tcId %d: %s", tc.TcID, tc.Comment)
+			// and the point is unchanged
+			require.True(t, p.Equal(newPoint()), "tcId %d: %s", tc.TcID, tc.Comment)
+		default:
+			t.Fatalf("tcId %d: unknown result %s", tc.TcID, tc.Result)
+		}
+		if tc.Point != "" {
+			q := fromRaw(decodeHex(t, tc.Point))
+			require.Equal(t, tc.Result == "valid", q.IsInCorrectGroup(), "tcId %d: %s", tc.TcID, tc.Comment)
+		}
+	}
+}
+
+func TestNegativeDecodingG1(t *testing.T) {
+	for _, b := range backends {
+		t.Run(b.String(), func(t *testing.T) {
+			testNegativeDecoding(t, "g1.json", func() GroupChecker { return newKyberG1(b.g1().zero(), nil, nil) },
+				func(raw []byte) GroupChecker {
+					p, err := b.g1().fromUncompressed(raw)
+					require.NoError(t, err)
+					return newKyberG1(p, nil, nil)
+				})
+		})
+	}
+}
+
+func TestNegativeDecodingG2(t *testing.T) {
+	for _, b := range backends {
+		t.Run(b.String(), func(t *testing.T) {
+			testNegativeDecoding(t, "g2.json", func() GroupChecker { return newKyberG2(b.g2().zero(), nil, nil) },
+				func(raw []byte) GroupChecker {
+					p, err := b.g2().fromUncompressed(raw)
+					require.NoError(t, err)
+					return newKyberG2(p, nil, nil)
+				})
+		})
+	}
+}
+
+func TestNegativeDecodingGT(t *testing.T) {
+	for _, b := range backends {
+		t.Run(b.String(), func(t *testing.T) {
+			testNegativeDecoding(t, "gt.json", func() GroupChecker { return newGroupGT(b).Point().(GroupChecker) }, nil)
+		})
+	}
+}
+
+func TestNegativeSignatures(t *testing.T) {
+	var corpus struct {
+		Notes  map[string]string `json:"notes"`
+		Groups []struct {
+			SignatureGroup string `json:"signatureGroup"`
+			Tests          []struct {
+				TcID    int      `json:"tcId"`
+				Comment string   `json:"comment"`
+				Pubkey  string   `json:"pubkey"`
+				Msg     string   `json:"msg"`
+				Sig     string   `json:"sig"`
+				Result  string   `json:"result"`
+				Flags   []string `json:"flags"`
+			} `json:"tests"`
+		} `json:"testGroups"`
+	}
+	loadJSON(t, "testdata/negative/signatures.json", &corpus)
+	suite := NewBLS12381Suite()
+	for _, group := range corpus.Groups {
+		scheme, keys, sigs := bls.NewSchemeOnG2(suite), suite.G1(), suite.G2()
+		if group.SignatureGroup == "G1" {
+			scheme, keys, sigs = bls.NewSchemeOnG1(suite), suite.G2(), suite.G1()
+		}
+		// verify returns the reason why the signature is rejected, or the empty string if it is valid
+		verify := func(pubkey, msg, sig []byte) string {
+			pk := keys.Point().(GroupChecker)
+			if err := pk.UnmarshalBinary(pubkey); err != nil {
+				return "InvalidPublicKey"
+			}
+			if pk.Equal(keys.Point().Null()) {
+				return "IdentityPublicKey"
+			}
+			s := sigs.Point().(GroupChecker)
+			if err := s.UnmarshalBinary(sig); err != nil {
+				return "InvalidSignature"
+			}
+			if scheme.Verify(pk, msg, sig) != nil {
+				return "VerificationFailure"
+			}
+			return ""
+		}
+		t.Run(group.SignatureGroup, func(t *testing.T) {
+			require.NotEmpty(t, group.Tests)
+			for _, tc := range group.Tests {
+				expected := ""
+				if tc.Result == "invalid" {
+					require.Len(t, tc.Flags, 1, "tcId %d", tc.TcID)
+					require.Contains(t, corpus.Notes, tc.Flags[0])
+					expected = tc.Flags[0]
+				}
+				reason := verify(decodeHex(t, tc.Pubkey), decodeHex(t, tc.Msg), decodeHex(t, tc.Sig))
+				require.Equal(t, expected, reason, "tcId %d: %s", tc.TcID, tc.Comment)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+// Package pedersen implements Pedersen commitments to scalars and to vectors of scalars, on G1 or G2 of
+// BLS12-381.
+//
+// The commitment to the values v_1...v_n with the blinding factor r is v_1 G_1 + ... + v_n G_n + r H. The
+// generators are hashed to the curve with a dedicated DST, so that nobody knows their discrete logarithms
+// with respect to each other: the commitments are perfectly hiding, and binding under the discrete
+// logarithm assumption. They are additively homomorphic: the sum of commitments is the commitment to the
+// sums of the values, with the sum of the blinding factors.
+package pedersen
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/drand/kyber"
+	bls "github.com/drand/kyber-bls12381"
+)
+
+var (
+	// DomainG1 is the DST of the hash to curve deriving the generators on G1.
+	DomainG1 = []byte("PEDERSEN_BLS12381G1_XMD:SHA-256_SSWU_RO_")
+	// DomainG2 is the DST of the hash to curve deriving the generators on G2.
+	DomainG2 = []byte("PEDERSEN_BLS12381G2_XMD:SHA-256_SSWU_RO_")
+)
+
+// ErrInvalidOpening is returned when a commitment does not open to the given values.
+var ErrInvalidOpening = errors.New("pedersen: invalid opening")
+
+var (
+	errGroup  = errors.New("pedersen: the group must be G1 or G2 of BLS12-381")
+	errLength = errors.New("pedersen: more values than generators")
+)
+
+// Params holds the generators of the commitments.
+type Params struct {
+	group kyber.Group
+	// G holds the generators of the values, G[0] being the one of the commitments to a single scalar.
+	G []kyber.Point
+	// H is the generator of the blinding factors.
+	H kyber.Point
+}
+
+// NewParams returns the generators of the commitments to at most n values in the group g, G1 or G2 of
+// BLS12-381. They are derived from label, which separates the generators of different applications: H is
+// the hash of the length of label as a big-endian uint32, label and "H", and G[i] the hash of the same prefix,
+// "G" and i as a big-endian uint32.
+func NewParams(g kyber.Group, label []byte, n int) (*Params, error) {
+	var dst []byte
+	switch g.Point().(type) {
+	case *bls.KyberG1:
+		dst = DomainG1
+	case *bls.KyberG2:
+		dst = DomainG2
+	default:
+		return nil, errGroup
+	}
+	if n < 1 {
+		return nil, fmt.Errorf("pedersen: invalid number of generators %d", n)
+	}
+	prefix := binary.BigEndian.AppendUint32(nil, uint32(len(label)))
+	prefix = append(prefix, label...)
+	generator := func(msg []byte) (kyber.Point, error) {
+		return bls.HashToGroup(g, append(prefix[:len(prefix):len(prefix)], msg...), dst)
+	}
+	h, err := generator([]byte("H"))
+	if err != nil {
+		return nil, err
+	}
+	params := &Params{group: g, G: make([]kyber.Point, n), H: h}
+	for i := range params.G {
+		if params.G[i], err = generator(binary.BigEndian.AppendUint32([]byte("G"), uint32(i))); err != nil {
+			return nil, err
+		}
+	}
+	return params, nil
+}
+
+// Commit returns the commitment v G[0] + r H to the scalar v, with the blinding factor r.
+func (p *Params) Commit(v, r kyber.Scalar) kyber.Point {
+	c := p.group.Point().Mul(v, p.G[0])
+	return c.Add(c, p.group.Point().Mul(r, p.H))
+}
+
+// CommitVector returns the commitment v[0] G[0] + ... + v[n-1] G[n-1] + r H to the values v, with the blinding
+// factor r. It returns an error if there are more values than generators.
+func (p *Params) CommitVector(v []kyber.Scalar, r kyber.Scalar) (kyber.Point, error) {
+	if len(v) > len(p.G) {
+		return nil, errLength
+	}
+	ps := append(p.G[:len(v):len(v)], p.H)
+	ss := append(v[:len(v):len(v)], r)
+	return bls.LinearCombination(p.group.Point(), ps, ss), nil
+}
+
+// Verify checks that c is the commitment to the scalar v with the blinding factor r, returning
+// ErrInvalidOpening if it is not.
+func (p *Params) Verify(c kyber.Point, v, r kyber.Scalar) error {
+	if !c.Equal(p.Commit(v, r)) {
+		return ErrInvalidOpening
+	}
+	return nil
+}
+
+// VerifyVector checks that c is the commitment to the values v with the blinding factor r, returning
+// ErrInvalidOpening if it is not.
+func (p *Params) VerifyVector(c kyber.Point, v []kyber.Scalar, r kyber.Scalar) error {
+	expected, err := p.CommitVector(v, r)
+	if err != nil {
+		return err
+	}
+	if !c.Equal(expected) {
+		return ErrInvalidOpening
+	}
+	return nil
+}
+
+// Add returns the sum of the commitments cs, which is the commitment to the sums of their values, with the
+// sum of their blinding factors.
+func (p *Params) Add(cs ...kyber.Point) kyber.Point {
+	sum := p.group.Point().Null()
+	for _, c := range cs {
+		sum.Add(sum, c)
+	}
+	return sum
+}
@@ -0,0 +1,128 @@
+package pedersen
+
+import (
+	"testing"
+
+	"github.com/drand/kyber"
+	bls "github.com/drand/kyber-bls12381"
+	"github.com/drand/kyber/util/random"
+	"github.com/stretchr/testify/require"
+)
+
+var backends = []bls.Backend{bls.BackendKilic, bls.BackendGnark}
+
+func randomScalars(g kyber.Group, n int) []kyber.Scalar {
+	ss := make([]kyber.Scalar, n)
+	for i := range ss {
+		ss[i] = g.Scalar().Pick(random.New())
+	}
+	return ss
+}
+
+func TestCommit(t *testing.T) {
+	for _, b := range backends {
+		suite := bls.NewBLS12381Suite(bls.WithBackend(b), bls.WithStrictDomains())
+		for _, g := range []kyber.Group{suite.G1(), suite.G2()} {
+			t.Run(b.String()+"/"+g.String(), func(t *testing.T) {
+				p, err := NewParams(g, []byte("test"), 4)
+				require.NoError(t, err)
+				v, r := randomScalars(g, 2), randomScalars(g, 2)
+				c0, c1 := p.Commit(v[0], r[0]), p.Commit(v[1], r[1])
+				require.NoError(t, p.Verify(c0, v[0], r[0]))
+				require.ErrorIs(t, p.Verify(c0, v[1], r[0]), ErrInvalidOpening)
+				require.ErrorIs(t, p.Verify(c0, v[0], r[1]), ErrInvalidOpening)
+
+				// the commitments are homomorphic, and mix with the points of g
+				sum := p.Add(c0, c1)
+				require.NoError(t, p.Verify(sum, g.Scalar().Add(v[0], v[1]), g.Scalar().Add(r[0], r[1])))
+				require.NoError(t, p.Verify(g.Point().Sub(sum, c1), v[0], r[0]))
+				require.True(t, p.Add().Equal(g.Point().Null()))
+			})
+		}
+	}
+}
+
+func TestCommitVector(t *testing.T) {
+	for _, b := range backends {
+		suite := bls.NewBLS12381Suite(bls.WithBackend(b))
+		for _, g := range []kyber.Group{suite.G1(), suite.G2()} {
+			t.Run(b.String()+"/"+g.String(), func(t *testing.T) {
+				p, err := NewParams(g, []byte("test"), 4)
+				require.NoError(t, err)
+				r := randomScalars(g, 2)
+				for _, n := range []int{0, 1, 3, 4} {
+					v := randomScalars(g, n)
+					c, err := p.CommitVector(v, r[0])
+					require.NoError(t, err)
+					require.NoError(t, p.VerifyVector(c, v, r[0]))
+					require.ErrorIs(t, p.VerifyVector(c, v, r[1]), ErrInvalidOpening)
+					if n == 1 {
+						require.True(t, c.Equal(p.Commit(v[0], r[0])))
+					}
+					if n > 0 {
+						w := append(randomScalars(g, 1), v[1:]...)
+						require.ErrorIs(t, p.VerifyVector(c, w, r[0]), ErrInvalidOpening)
+						// missing values are zero
+						padded := append(append([]kyber.Scalar(nil), v...), g.Scalar().Zero())
+						if len(padded) <= 4 {
+							require.NoError(t, p.VerifyVector(c, padded, r[0]))
+						}
+					}
+				}
+
+				v, w := randomScalars(g, 4), randomScalars(g, 4)
+				cv, err := p.CommitVector(v, r[0])
+				require.NoError(t, err)
+				cw, err := p.CommitVector(w, r[1])
+				require.NoError(t, err)
+				sums := make([]kyber.Scalar, 4)
+				for i := range sums {
+					sums[i] = g.Scalar().Add(v[i], w[i])
+				}
+				require.NoError(t, p.VerifyVector(p.Add(cv, cw), sums, g.Scalar().Add(r[0], r[1])))
+
+				_, err = p.CommitVector(randomScalars(g, 5), r[0])
+				require.Error(t, err)
+				require.Error(t, p.VerifyVector(cv, randomScalars(g, 5), r[0]))
+			})
+		}
+	}
+}
+
+func TestNewParams(t *testing.T) {
+	suite := bls.NewBLS12381Suite()
+	for _, g := range []kyber.Group{suite.G1(), suite.G2()} {
+		p, err := NewParams(g, []byte("app"), 3)
+		require.NoError(t, err)
+		// the generators are distinct and do not depend on their number
+		q, err := NewParams(g, []byte("app"), 2)
+		require.NoError(t, err)
+		require.True(t, p.H.Equal(q.H))
+		require.True(t, p.G[1].Equal(q.G[1]))
+		ps := append([]kyber.Point{p.H}, p.G...)
+		for i := range ps {
+			for j := i + 1; j < len(ps); j++ {
+				require.False(t, ps[i].Equal(ps[j]))
+			}
+		}
+		other, err := NewParams(g, []byte("other"), 1)
+		require.NoError(t, err)
+		require.False(t, p.H.Equal(other.H))
+		require.False(t, p.G[0].Equal(other.G[0]))
+	}
+
+	// G[2] on G1 hashes 0x00000003 || "app" || "G" || 0x00000002
+	p, err := NewParams(suite.G1(), []byte("app"), 3)
+	require.NoError(t, err)
+	msg := []byte{0, 0, 0, 3, 'a', 'p', 'p', 'G', 0, 0, 0, 2}
+	expected, err := bls.NullKyberG1(DomainG1...).Hash(msg).MarshalBinary()
+	require.NoError(t, err)
+	actual, err := p.G[2].MarshalBinary()
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+
+	_, err = NewParams(suite.G1(), nil, 0)
+	require.Error(t, err)
+	_, err = NewParams(suite.GT(), nil, 1)
+	require.Error(t, err)
+}
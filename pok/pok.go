@@ -0,0 +1,152 @@
+// Package pok implements non-interactive Schnorr proofs of knowledge of the secret key of a public key on G1
+// or G2 of BLS12-381, e.g. to register BLS public keys against rogue key attacks.
+//
+// The proof of knowledge of x for the public key P = xG, G being the generator of the group, is the
+// challenge c and the response r = k - cx, with the nonce k derived from x and P as in RFC 6979, so that the
+// proofs are deterministic. The verifier recomputes the commitment kG = rG + cP and checks the challenge.
+// Both the nonce and the challenge are derived with hash_to_field of RFC 9380:
+//
+//	k = hash_to_scalar(x || P, "NONCE_" || DST)
+//	c = hash_to_scalar(G || P || kG, DST)
+//
+// with the big-endian encoding of x and the compressed encodings of the points.
+package pok
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/drand/kyber"
+	bls "github.com/drand/kyber-bls12381"
+	"github.com/drand/kyber/pairing"
+)
+
+var (
+	// DomainG1 is the default DST of the proofs for public keys on G1.
+	DomainG1 = []byte("POK_BLS12381G1_XMD:SHA-256_RO_")
+	// DomainG2 is the default DST of the proofs for public keys on G2.
+	DomainG2 = []byte("POK_BLS12381G2_XMD:SHA-256_RO_")
+)
+
+// ProofSize is the size of the encoding of a proof: the challenge followed by the response.
+const ProofSize = 64
+
+// ErrInvalidProof is returned when a proof does not verify.
+var ErrInvalidProof = errors.New("pok: invalid proof")
+
+var (
+	errIdentity = errors.New("pok: the public key is the identity")
+	errZero     = errors.New("pok: the secret key is zero")
+)
+
+// Proof is a proof of knowledge of a secret key.
+type Proof struct {
+	C, R kyber.Scalar
+}
+
+// Scheme proves the knowledge of secret keys of the public keys of a group, under a DST.
+type Scheme struct {
+	group kyber.Group
+	dst   []byte
+}
+
+// NewSchemeOnG1 returns the scheme for public keys on G1, with the given DST, or DomainG1 if it is empty.
+func NewSchemeOnG1(suite pairing.Suite, dst []byte) *Scheme {
+	if len(dst) == 0 {
+		dst = DomainG1
+	}
+	return &Scheme{group: suite.G1(), dst: dst}
+}
+
+// NewSchemeOnG2 returns the scheme for public keys on G2, with the given DST, or DomainG2 if it is empty.
+func NewSchemeOnG2(suite pairing.Suite, dst []byte) *Scheme {
+	if len(dst) == 0 {
+		dst = DomainG2
+	}
+	return &Scheme{group: suite.G2(), dst: dst}
+}
+
+// Prove returns the proof of knowledge of the secret key, for the public key secret * G.
+func (s *Scheme) Prove(secret kyber.Scalar) (*Proof, error) {
+	if secret.Equal(s.group.Scalar().Zero()) {
+		return nil, errZero
+	}
+	public := s.group.Point().Mul(secret, nil)
+	x, err := secret.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	p, err := public.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	k := bls.HashToScalar(append(x, p...), append([]byte("NONCE_"), s.dst...))
+	c, err := s.challenge(public, s.group.Point().Mul(k, nil))
+	if err != nil {
+		return nil, err
+	}
+	r := s.group.Scalar().Mul(c, secret)
+	return &Proof{C: c, R: r.Sub(k, r)}, nil
+}
+
+// Verify checks the proof of knowledge of the secret key of public, returning ErrInvalidProof if it does not
+// verify. The identity is rejected, and public must be in the prime order subgroup, as checked by its
+// UnmarshalBinary.
+func (s *Scheme) Verify(public kyber.Point, proof *Proof) error {
+	if public.Equal(s.group.Point().Null()) {
+		return errIdentity
+	}
+	// kG = rG + cP
+	commitment := s.group.Point().Mul(proof.R, nil)
+	commitment.Add(commitment, s.group.Point().Mul(proof.C, public))
+	c, err := s.challenge(public, commitment)
+	if err != nil {
+		return err
+	}
+	if !c.Equal(proof.C) {
+		return ErrInvalidProof
+	}
+	return nil
+}
+
+// challenge returns the hash of the generator, the public key and the commitment.
+func (s *Scheme) challenge(public, commitment kyber.Point) (kyber.Scalar, error) {
+	var msg []byte
+	for _, p := range []kyber.Point{s.group.Point().Base(), public, commitment} {
+		buf, err := p.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		msg = append(msg, buf...)
+	}
+	return bls.HashToScalar(msg, s.dst), nil
+}
+
+// MarshalBinary returns the encoding of the proof, the challenge followed by the response, both big-endian.
+func (p *Proof) MarshalBinary() ([]byte, error) {
+	c, err := p.C.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	r, err := p.R.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return append(c, r...), nil
+}
+
+// UnmarshalBinary sets the proof from its encoding, rejecting scalars which are not reduced.
+func (p *Proof) UnmarshalBinary(buf []byte) error {
+	if len(buf) != ProofSize {
+		return fmt.Errorf("pok: invalid proof length %d", len(buf))
+	}
+	c, r := bls.NewKyberScalar(), bls.NewKyberScalar()
+	if err := c.UnmarshalBinary(buf[:ProofSize/2]); err != nil {
+		return fmt.Errorf("pok: invalid challenge: %w", err)
+	}
+	if err := r.UnmarshalBinary(buf[ProofSize/2:]); err != nil {
+		return fmt.Errorf("pok: invalid response: %w", err)
+	}
+	*p = Proof{C: c, R: r}
+	return nil
+}
@@ -0,0 +1,103 @@
+package pok
+
+import (
+	"encoding/hex"
+	"testing"
+
+	bls "github.com/drand/kyber-bls12381"
+	"github.com/drand/kyber/pairing"
+	"github.com/drand/kyber/util/random"
+	"github.com/stretchr/testify/require"
+)
+
+var backends = []bls.Backend{bls.BackendKilic, bls.BackendGnark}
+
+func schemes(suite pairing.Suite, dst []byte) map[string]*Scheme {
+	return map[string]*Scheme{"G1": NewSchemeOnG1(suite, dst), "G2": NewSchemeOnG2(suite, dst)}
+}
+
+func TestProof(t *testing.T) {
+	for _, b := range backends {
+		suite := bls.NewBLS12381Suite(bls.WithBackend(b))
+		for name, s := range schemes(suite, nil) {
+			t.Run(b.String()+"/"+name, func(t *testing.T) {
+				x := s.group.Scalar().Pick(random.New())
+				public := s.group.Point().Mul(x, nil)
+				proof, err := s.Prove(x)
+				require.NoError(t, err)
+				require.NoError(t, s.Verify(public, proof))
+
+				// the proofs are deterministic
+				again, err := s.Prove(x)
+				require.NoError(t, err)
+				require.Equal(t, proof, again)
+
+				other := s.group.Point().Pick(random.New())
+				require.ErrorIs(t, s.Verify(other, proof), ErrInvalidProof)
+				swapped := &Proof{C: proof.R, R: proof.C}
+				require.ErrorIs(t, s.Verify(public, swapped), ErrInvalidProof)
+
+				buf, err := proof.MarshalBinary()
+				require.NoError(t, err)
+				require.Len(t, buf, ProofSize)
+				decoded := new(Proof)
+				require.NoError(t, decoded.UnmarshalBinary(buf))
+				require.NoError(t, s.Verify(public, decoded))
+			})
+		}
+	}
+}
+
+func TestDomain(t *testing.T) {
+	suite := bls.NewBLS12381Suite()
+	for name, s := range schemes(suite, nil) {
+		x := s.group.Scalar().Pick(random.New())
+		public := s.group.Point().Mul(x, nil)
+		proof, err := s.Prove(x)
+		require.NoError(t, err)
+		custom := schemes(suite, []byte("MY_APP_POK_"))[name]
+		require.ErrorIs(t, custom.Verify(public, proof), ErrInvalidProof)
+		proof, err = custom.Prove(x)
+		require.NoError(t, err)
+		require.NoError(t, custom.Verify(public, proof))
+		require.ErrorIs(t, s.Verify(public, proof), ErrInvalidProof)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	suite := bls.NewBLS12381Suite()
+	s := NewSchemeOnG1(suite, nil)
+	_, err := s.Prove(suite.G1().Scalar().Zero())
+	require.Error(t, err)
+	proof, err := s.Prove(suite.G1().Scalar().One())
+	require.NoError(t, err)
+	err = s.Verify(suite.G1().Point().Null(), proof)
+	require.Error(t, err)
+
+	var p Proof
+	require.Error(t, p.UnmarshalBinary(make([]byte, ProofSize+1)))
+	buf := make([]byte, ProofSize)
+	for i := ProofSize / 2; i < ProofSize; i++ {
+		buf[i] = 0xff
+	}
+	require.Error(t, p.UnmarshalBinary(buf), "non canonical response")
+}
+
+// TestKnownAnswer pins the proofs of the secret key 42 with the default DSTs, which were computed with this
+// package, so that they do not change.
+func TestKnownAnswer(t *testing.T) {
+	suite := bls.NewBLS12381Suite()
+	for name, expected := range map[string]string{
+		"G1": "3dc784286ff8b1bbbf184e358e85fa9fc6b991c39b43d3a0475c59fe86d83244" +
+			"616c3a8fa3e88ae2c78b1306c0b2ac84e2efe8b314d71ae57a613f16c65e7aac",
+		"G2": "6729adaa3441429e801d36cc7934f731fd9ad7e5b071f7a47bae4208e0c582af" +
+			"4cae834104d5a0bfbb04d9cbfc6dc37c7aca7642e5062f6b0368b3716648ab5d",
+	} {
+		s := schemes(suite, nil)[name]
+		proof, err := s.Prove(s.group.Scalar().SetInt64(42))
+		require.NoError(t, err)
+		buf, err := proof.MarshalBinary()
+		require.NoError(t, err)
+		require.Equal(t, expected, hex.EncodeToString(buf), name)
+	}
+}
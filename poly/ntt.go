@@ -0,0 +1,163 @@
+package poly
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/drand/kyber"
+	"github.com/drand/kyber/group/mod"
+)
+
+// MaxDomainSize is the largest power of two dividing r - 1, r being the order of the scalar field of
+// BLS12-381, and so the size of the largest domain. It is an int64 since it does not fit in an int on 32-bit
+// platforms, where the domains have at most 2^30 elements.
+const MaxDomainSize int64 = 1 << 32
+
+// generator is a generator of the multiplicative group of the scalar field of BLS12-381, from which the roots
+// of unity are derived, as by EIP-4844 and gnark-crypto.
+const generator = 7
+
+var (
+	errDomainSize = errors.New("poly: the domain size must be a power of two dividing r - 1")
+	errNTTLength  = errors.New("poly: too many values for the domain")
+)
+
+// Domain is the multiplicative subgroup of the scalar field generated by a root of unity ω of order Size, a
+// power of two, or its coset shifted by Shift: the points Shift * ω^i.
+type Domain struct {
+	group kyber.Group
+	Size  int
+	// Generator is ω, the primitive root of unity of order Size.
+	Generator kyber.Scalar
+	// Shift is one for the subgroup itself.
+	Shift kyber.Scalar
+}
+
+// NewDomain returns the subgroup of order size, a power of two dividing r - 1, at most MaxDomainSize for
+// BLS12-381.
+func NewDomain(g kyber.Group, size int) (*Domain, error) {
+	order := g.Scalar().(*mod.Int).M
+	exp := new(big.Int).Sub(order, big.NewInt(1))
+	if size <= 0 || size&(size-1) != 0 || new(big.Int).Mod(exp, big.NewInt(int64(size))).Sign() != 0 {
+		return nil, fmt.Errorf("%w: %d", errDomainSize, size)
+	}
+	exp.Div(exp, big.NewInt(int64(size)))
+	root := g.Scalar().(*mod.Int).Exp(g.Scalar().SetInt64(generator), exp)
+	return &Domain{group: g, Size: size, Generator: root, Shift: g.Scalar().One()}, nil
+}
+
+// Coset returns the domain shifted by shift, which must not be in the subgroup for the cosets to be
+// disjoint, e.g. the generator 7 of the multiplicative group.
+func (d *Domain) Coset(shift kyber.Scalar) *Domain {
+	return &Domain{group: d.group, Size: d.Size, Generator: d.Generator, Shift: shift.Clone()}
+}
+
+// Element returns the point of index i of the domain, Shift * ω^i.
+func (d *Domain) Element(i int) kyber.Scalar {
+	x := d.group.Scalar().(*mod.Int).Exp(d.Generator, big.NewInt(int64(i)))
+	return x.Mul(x, d.Shift)
+}
+
+// Elements returns the points of the domain, in their natural order.
+func (d *Domain) Elements() []kyber.Scalar {
+	return d.powers(d.Generator, d.Shift, d.Size)
+}
+
+// powers returns start * x^i for i < n.
+func (d *Domain) powers(x, start kyber.Scalar, n int) []kyber.Scalar {
+	p := make([]kyber.Scalar, n)
+	if n == 0 {
+		return p
+	}
+	p[0] = start.Clone()
+	for i := 1; i < n; i++ {
+		p[i] = d.group.Scalar().Mul(p[i-1], x)
+	}
+	return p
+}
+
+// NTT returns the evaluations of the polynomial p, of at most Size coefficients, at the points of the domain
+// in their natural order.
+func (d *Domain) NTT(p []kyber.Scalar) ([]kyber.Scalar, error) {
+	if len(p) > d.Size {
+		return nil, errNTTLength
+	}
+	a := zeros(d.group, d.Size)
+	// p(Shift X) has the coefficients p_i Shift^i
+	shift := d.group.Scalar().One()
+	for i := range p {
+		a[i].Mul(p[i], shift)
+		shift.Mul(shift, d.Shift)
+	}
+	d.transform(a, d.Generator)
+	return a, nil
+}
+
+// INTT returns the polynomial of less than Size coefficients with the evaluations evals at the points of the
+// domain in their natural order.
+func (d *Domain) INTT(evals []kyber.Scalar) ([]kyber.Scalar, error) {
+	if len(evals) != d.Size {
+		return nil, errNTTLength
+	}
+	a := clone(evals)
+	d.transform(a, d.group.Scalar().Inv(d.Generator))
+	// divide by Size, and undo the shift
+	factor := d.group.Scalar().Inv(d.group.Scalar().SetInt64(int64(d.Size)))
+	unshift := d.group.Scalar().Inv(d.Shift)
+	for i := range a {
+		a[i].Mul(a[i], factor)
+		factor.Mul(factor, unshift)
+	}
+	return a, nil
+}
+
+// transform replaces a, of length Size, by its evaluations at the powers of the root of unity w of order
+// Size, with the iterative radix-2 Cooley-Tukey algorithm.
+func (d *Domain) transform(a []kyber.Scalar, w kyber.Scalar) {
+	n := len(a)
+	for i, j := range bitReversal(n) {
+		if i < j {
+			a[i], a[j] = a[j], a[i]
+		}
+	}
+	// the twiddles of the last stage, w^i for i < n/2, the previous stages using every other one
+	twiddles := d.powers(w, d.group.Scalar().One(), n/2)
+	t := d.group.Scalar()
+	for size := 2; size <= n; size <<= 1 {
+		half, step := size/2, n/size
+		for start := 0; start < n; start += size {
+			for k := 0; k < half; k++ {
+				t.Mul(a[start+k+half], twiddles[k*step])
+				a[start+k+half].Sub(a[start+k], t)
+				a[start+k].Add(a[start+k], t)
+			}
+		}
+	}
+}
+
+// bitReversal returns the permutation of [0, n) reversing the bits of the indices, n being a power of two.
+func bitReversal(n int) []int {
+	bits := 0
+	for 1<<bits < n {
+		bits++
+	}
+	perm := make([]int, n)
+	for i := range perm {
+		j := 0
+		for b := 0; b < bits; b++ {
+			j |= (i >> b & 1) << (bits - 1 - b)
+		}
+		perm[i] = j
+	}
+	return perm
+}
+
+// BitReverse returns the elements of s, of a power of two length, in bit-reversed order.
+func BitReverse[T any](s []T) []T {
+	out := make([]T, len(s))
+	for i, j := range bitReversal(len(s)) {
+		out[j] = s[i]
+	}
+	return out
+}
@@ -0,0 +1,196 @@
+// Package poly implements polynomial arithmetic over the scalar field of BLS12-381: radix-2 number theoretic
+// transforms over domains of up to 2^32 elements, multiplication, division, interpolation and batched
+// inversion. Polynomials are slices of coefficients, starting with the constant term, whose scalars are of the
+// group passed to the functions.
+package poly
+
+import (
+	"errors"
+
+	"github.com/drand/kyber"
+)
+
+var (
+	errDivisionByZero = errors.New("poly: division by zero")
+	errDuplicate      = errors.New("poly: duplicate interpolation point")
+	errLength         = errors.New("poly: inputs of different lengths")
+)
+
+// Evaluate returns the value of p at x, with Horner's method.
+func Evaluate(g kyber.Group, p []kyber.Scalar, x kyber.Scalar) kyber.Scalar {
+	y := g.Scalar().Zero()
+	for i := len(p) - 1; i >= 0; i-- {
+		y.Mul(y, x).Add(y, p[i])
+	}
+	return y
+}
+
+// Add returns a + b.
+func Add(g kyber.Group, a, b []kyber.Scalar) []kyber.Scalar {
+	if len(a) < len(b) {
+		a, b = b, a
+	}
+	r := clone(a)
+	for i := range b {
+		r[i].Add(r[i], b[i])
+	}
+	return r
+}
+
+// Sub returns a - b.
+func Sub(g kyber.Group, a, b []kyber.Scalar) []kyber.Scalar {
+	r := make([]kyber.Scalar, max(len(a), len(b)))
+	for i := range r {
+		r[i] = g.Scalar().Zero()
+		if i < len(a) {
+			r[i].Set(a[i])
+		}
+		if i < len(b) {
+			r[i].Sub(r[i], b[i])
+		}
+	}
+	return r
+}
+
+// mulNTTThreshold is the length of the product from which Mul uses NTTs rather than the schoolbook method.
+const mulNTTThreshold = 64
+
+// Mul returns a * b, with NTTs for large polynomials.
+func Mul(g kyber.Group, a, b []kyber.Scalar) []kyber.Scalar {
+	if len(a) == 0 || len(b) == 0 {
+		return nil
+	}
+	n := len(a) + len(b) - 1
+	if n < mulNTTThreshold {
+		r := zeros(g, n)
+		for i := range a {
+			for j := range b {
+				r[i+j].Add(r[i+j], g.Scalar().Mul(a[i], b[j]))
+			}
+		}
+		return r
+	}
+	size := 1
+	for size < n {
+		size <<= 1
+	}
+	// the degrees are below the 2^32 roots of unity of the field for any polynomial that fits in memory
+	d, err := NewDomain(g, size)
+	if err != nil {
+		panic(err)
+	}
+	ea, _ := d.NTT(a)
+	eb, _ := d.NTT(b)
+	for i := range ea {
+		ea[i].Mul(ea[i], eb[i])
+	}
+	r, _ := d.INTT(ea)
+	return r[:n]
+}
+
+// Div returns the quotient and the remainder of the division of a by b, whose leading coefficient must not
+// be zero.
+func Div(g kyber.Group, a, b []kyber.Scalar) (q, r []kyber.Scalar, err error) {
+	if len(b) == 0 || b[len(b)-1].Equal(g.Scalar().Zero()) {
+		return nil, nil, errDivisionByZero
+	}
+	r = clone(a)
+	if len(a) < len(b) {
+		return nil, r, nil
+	}
+	lead := g.Scalar().Inv(b[len(b)-1])
+	q = make([]kyber.Scalar, len(a)-len(b)+1)
+	for i := len(q) - 1; i >= 0; i-- {
+		q[i] = g.Scalar().Mul(r[i+len(b)-1], lead)
+		for j := range b {
+			r[i+j].Sub(r[i+j], g.Scalar().Mul(q[i], b[j]))
+		}
+	}
+	return q, r[:len(b)-1], nil
+}
+
+// Vanishing returns (X - x_0)...(X - x_k), which is zero at the points xs.
+func Vanishing(g kyber.Group, xs []kyber.Scalar) []kyber.Scalar {
+	v := []kyber.Scalar{g.Scalar().One()}
+	for _, x := range xs {
+		// v * (X - x)
+		next := make([]kyber.Scalar, len(v)+1)
+		next[len(v)] = v[len(v)-1].Clone()
+		for i := len(v) - 1; i > 0; i-- {
+			next[i] = g.Scalar().Sub(v[i-1], g.Scalar().Mul(x, v[i]))
+		}
+		next[0] = g.Scalar().Neg(g.Scalar().Mul(x, v[0]))
+		v = next
+	}
+	return v
+}
+
+// Interpolate returns the polynomial of degree less than len(xs) with the values ys at the distinct points
+// xs, with the Lagrange formula.
+func Interpolate(g kyber.Group, xs, ys []kyber.Scalar) ([]kyber.Scalar, error) {
+	if len(xs) != len(ys) {
+		return nil, errLength
+	}
+	p := zeros(g, len(xs))
+	z := Vanishing(g, xs)
+	// the denominators Π_{j != i} (x_i - x_j) of the Lagrange polynomials, inverted at once
+	dens := make([]kyber.Scalar, len(xs))
+	for i, x := range xs {
+		dens[i] = g.Scalar().One()
+		for j, xj := range xs {
+			if j != i {
+				dens[i].Mul(dens[i], g.Scalar().Sub(x, xj))
+			}
+		}
+	}
+	inv, err := BatchInvert(g, dens)
+	if err != nil {
+		return nil, errDuplicate
+	}
+	for i, x := range xs {
+		// L_i(X) = Z(X) / (X - x_i) / Π_{j != i} (x_i - x_j)
+		num, _, _ := Div(g, z, []kyber.Scalar{g.Scalar().Neg(x), g.Scalar().One()})
+		c := g.Scalar().Mul(ys[i], inv[i])
+		for k := range num {
+			p[k].Add(p[k], g.Scalar().Mul(c, num[k]))
+		}
+	}
+	return p, nil
+}
+
+// BatchInvert returns the inverses of the scalars xs with a single inversion, with Montgomery's trick. It
+// returns an error if one of them is zero.
+func BatchInvert(g kyber.Group, xs []kyber.Scalar) ([]kyber.Scalar, error) {
+	// prefix[i] is the product of xs[:i]
+	prefix := make([]kyber.Scalar, len(xs)+1)
+	prefix[0] = g.Scalar().One()
+	for i, x := range xs {
+		prefix[i+1] = g.Scalar().Mul(prefix[i], x)
+	}
+	if prefix[len(xs)].Equal(g.Scalar().Zero()) {
+		return nil, errDivisionByZero
+	}
+	inv := make([]kyber.Scalar, len(xs))
+	acc := g.Scalar().Inv(prefix[len(xs)])
+	for i := len(xs) - 1; i >= 0; i-- {
+		inv[i] = g.Scalar().Mul(acc, prefix[i])
+		acc.Mul(acc, xs[i])
+	}
+	return inv, nil
+}
+
+func zeros(g kyber.Group, n int) []kyber.Scalar {
+	z := make([]kyber.Scalar, n)
+	for i := range z {
+		z[i] = g.Scalar().Zero()
+	}
+	return z
+}
+
+func clone(p []kyber.Scalar) []kyber.Scalar {
+	c := make([]kyber.Scalar, len(p))
+	for i := range p {
+		c[i] = p[i].Clone()
+	}
+	return c
+}
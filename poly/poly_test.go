@@ -0,0 +1,157 @@
+package poly
+
+import (
+	"math/big"
+	"strconv"
+	"testing"
+
+	"github.com/drand/kyber"
+	bls "github.com/drand/kyber-bls12381"
+	"github.com/drand/kyber/group/mod"
+	"github.com/drand/kyber/util/random"
+	"github.com/stretchr/testify/require"
+)
+
+var group = bls.NewBLS12381Suite().G1()
+
+func randomPoly(n int) []kyber.Scalar {
+	p := make([]kyber.Scalar, n)
+	for i := range p {
+		p[i] = group.Scalar().Pick(random.New())
+	}
+	return p
+}
+
+func requireEqual(t *testing.T, a, b []kyber.Scalar) {
+	t.Helper()
+	require.Len(t, b, len(a))
+	for i := range a {
+		require.True(t, a[i].Equal(b[i]), "coefficient %d", i)
+	}
+}
+
+func TestNTT(t *testing.T) {
+	shift := group.Scalar().SetInt64(generator)
+	for _, size := range []int{1, 2, 8, 16} {
+		d, err := NewDomain(group, size)
+		require.NoError(t, err)
+		for _, d := range []*Domain{d, d.Coset(shift)} {
+			p := randomPoly(size - size/4)
+			evals, err := d.NTT(p)
+			require.NoError(t, err)
+			for i, x := range d.Elements() {
+				require.True(t, x.Equal(d.Element(i)))
+				require.True(t, evals[i].Equal(Evaluate(group, p, x)))
+			}
+			back, err := d.INTT(evals)
+			require.NoError(t, err)
+			requireEqual(t, append(p, zeros(group, size/4)...), back)
+		}
+		_, err = d.NTT(randomPoly(size + 1))
+		require.Error(t, err)
+		_, err = d.INTT(randomPoly(size + 1))
+		require.Error(t, err)
+	}
+}
+
+func TestNewDomain(t *testing.T) {
+	for _, size := range []int{0, 3, 12} {
+		_, err := NewDomain(group, size)
+		require.Error(t, err, "size %d", size)
+	}
+
+	if strconv.IntSize == 32 {
+		t.Skip("MaxDomainSize does not fit in an int")
+	}
+	maxSize := MaxDomainSize
+	d, err := NewDomain(group, int(maxSize))
+	require.NoError(t, err)
+	// ω^(2^31) = -1 for a primitive root of order 2^32
+	half := group.Scalar().(*mod.Int).Exp(d.Generator, big.NewInt(maxSize/2))
+	require.True(t, half.Equal(group.Scalar().Neg(group.Scalar().One())))
+	_, err = NewDomain(group, int(maxSize<<1))
+	require.Error(t, err)
+}
+
+func TestMul(t *testing.T) {
+	for _, n := range [][2]int{{0, 3}, {1, 1}, {3, 5}, {40, 60}, {100, 1}} {
+		a, b := randomPoly(n[0]), randomPoly(n[1])
+		ab := Mul(group, a, b)
+		if n[0] == 0 {
+			require.Empty(t, ab)
+			continue
+		}
+		require.Len(t, ab, n[0]+n[1]-1)
+		x := group.Scalar().Pick(random.New())
+		require.True(t, Evaluate(group, ab, x).Equal(group.Scalar().Mul(Evaluate(group, a, x), Evaluate(group, b, x))))
+	}
+}
+
+func TestAddSub(t *testing.T) {
+	a, b := randomPoly(5), randomPoly(3)
+	x := group.Scalar().Pick(random.New())
+	ya, yb := Evaluate(group, a, x), Evaluate(group, b, x)
+	require.True(t, Evaluate(group, Add(group, a, b), x).Equal(group.Scalar().Add(ya, yb)))
+	require.True(t, Evaluate(group, Add(group, b, a), x).Equal(group.Scalar().Add(ya, yb)))
+	require.True(t, Evaluate(group, Sub(group, b, a), x).Equal(group.Scalar().Sub(yb, ya)))
+	requireEqual(t, a, Sub(group, Add(group, a, b), b)[:5])
+}
+
+func TestDiv(t *testing.T) {
+	for _, n := range [][2]int{{10, 3}, {10, 1}, {3, 5}, {7, 7}} {
+		a, b := randomPoly(n[0]), randomPoly(n[1])
+		q, r, err := Div(group, a, b)
+		require.NoError(t, err)
+		require.Len(t, r, min(n[0], n[1]-1))
+		// a = q b + r
+		requireEqual(t, a, Add(group, Mul(group, q, b), r)[:n[0]])
+	}
+	_, _, err := Div(group, randomPoly(3), nil)
+	require.Error(t, err)
+	_, _, err = Div(group, randomPoly(3), []kyber.Scalar{group.Scalar().One(), group.Scalar().Zero()})
+	require.Error(t, err)
+}
+
+func TestInterpolate(t *testing.T) {
+	for _, n := range []int{1, 2, 7} {
+		p := randomPoly(n)
+		xs := randomPoly(n)
+		ys := make([]kyber.Scalar, n)
+		for i, x := range xs {
+			ys[i] = Evaluate(group, p, x)
+		}
+		q, err := Interpolate(group, xs, ys)
+		require.NoError(t, err)
+		requireEqual(t, p, q)
+
+		for _, x := range xs {
+			require.True(t, Evaluate(group, Vanishing(group, xs), x).Equal(group.Scalar().Zero()))
+		}
+	}
+	xs := randomPoly(3)
+	xs[2] = xs[0]
+	_, err := Interpolate(group, xs, randomPoly(3))
+	require.Error(t, err)
+	_, err = Interpolate(group, xs, randomPoly(2))
+	require.Error(t, err)
+}
+
+func TestBatchInvert(t *testing.T) {
+	xs := randomPoly(9)
+	inv, err := BatchInvert(group, xs)
+	require.NoError(t, err)
+	for i := range xs {
+		require.True(t, group.Scalar().Mul(xs[i], inv[i]).Equal(group.Scalar().One()))
+	}
+	inv, err = BatchInvert(group, nil)
+	require.NoError(t, err)
+	require.Empty(t, inv)
+	xs[4] = group.Scalar().Zero()
+	_, err = BatchInvert(group, xs)
+	require.Error(t, err)
+}
+
+func TestBitReverse(t *testing.T) {
+	require.Equal(t, []int{0, 4, 2, 6, 1, 5, 3, 7}, BitReverse([]int{0, 1, 2, 3, 4, 5, 6, 7}))
+	require.Equal(t, []int{7}, BitReverse([]int{7}))
+}
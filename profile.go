@@ -0,0 +1,246 @@
+package bls
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/drand/kyber"
+	"github.com/drand/kyber/group/mod"
+)
+
+// Profile is a serialization format of points, GT elements and scalars, for the exchange with other
+// BLS12-381 libraries. The decoding of points checks that they are in the prime order subgroup.
+type Profile int
+
+const (
+	// ProfileZCash is the format of MarshalBinary: the ZCash compressed format of points, and big-endian scalars
+	// and GT elements.
+	ProfileZCash Profile = iota
+	// ProfileZCashUncompressed is ProfileZCash with points in the ZCash uncompressed format.
+	ProfileZCashUncompressed
+	// ProfileArkworks03 is the CanonicalSerialize format of arkworks 0.3, with compressed points: little-endian
+	// field elements, Fp2 elements as c0 || c1, and the flags in the most significant bits of the last byte.
+	// It is not the format of arkworks 0.4 and later, whose points use ProfileZCash, while their scalars and GT
+	// elements are still encoded as by ProfileArkworks03.
+	ProfileArkworks03
+	// ProfileArkworks03Uncompressed is ProfileArkworks03 with uncompressed points.
+	ProfileArkworks03Uncompressed
+)
+
+// ProfileGnark and ProfileGnarkUncompressed are aliases of the ZCash profiles, which gnark-crypto uses: Bytes
+// and RawBytes for points, Bytes for GT elements and fr.Element.Bytes for scalars.
+const (
+	ProfileGnark             = ProfileZCash
+	ProfileGnarkUncompressed = ProfileZCashUncompressed
+)
+
+var (
+	errProfile         = errors.New("bls12-381: unknown serialization profile")
+	errProfileEncoding = errors.New("bls12-381: invalid encoding for the serialization profile")
+)
+
+func (prof Profile) String() string {
+	switch prof {
+	case ProfileZCash:
+		return "zcash"
+	case ProfileZCashUncompressed:
+		return "zcash-uncompressed"
+	case ProfileArkworks03:
+		return "arkworks-0.3"
+	case ProfileArkworks03Uncompressed:
+		return "arkworks-0.3-uncompressed"
+	}
+	return fmt.Sprintf("Profile(%d)", int(prof))
+}
+
+func (prof Profile) arkworks() bool {
+	return prof == ProfileArkworks03 || prof == ProfileArkworks03Uncompressed
+}
+
+func (prof Profile) uncompressed() bool {
+	return prof == ProfileZCashUncompressed || prof == ProfileArkworks03Uncompressed
+}
+
+// Marshal returns the encoding of m in the profile. m is a *KyberG1, *KyberG2, *KyberGT or a scalar of this
+// package.
+func (prof Profile) Marshal(m kyber.Marshaling) ([]byte, error) {
+	if prof < ProfileZCash || prof > ProfileArkworks03Uncompressed {
+		return nil, errProfile
+	}
+	switch v := m.(type) {
+	case *KyberG1:
+		return prof.marshalPoint(v.p), nil
+	case *KyberG2:
+		return prof.marshalPoint(v.p), nil
+	case *KyberGT:
+		buf, err := v.MarshalBinary()
+		if err != nil || !prof.arkworks() {
+			return buf, err
+		}
+		return reverse(buf), nil
+	case *mod.Int:
+		if v.M == nil || v.M.Cmp(curveOrder) != 0 {
+			break
+		}
+		buf, err := v.MarshalBinary()
+		if err != nil || !prof.arkworks() {
+			return buf, err
+		}
+		return reverse(buf), nil
+	}
+	return nil, fmt.Errorf("%w: cannot marshal %T", ErrWrongType, m)
+}
+
+// Unmarshal sets m from its encoding in the profile. m is a *KyberG1, *KyberG2, *KyberGT or a scalar of this
+// package.
+func (prof Profile) Unmarshal(m kyber.Marshaling, buf []byte) error {
+	if prof < ProfileZCash || prof > ProfileArkworks03Uncompressed {
+		return errProfile
+	}
+	switch v := m.(type) {
+	case *KyberG1:
+		v.backend = backendOrDefault(v.backend)
+		p, err := prof.unmarshalPoint(v.backend.g1(), buf, 48)
+		if err != nil {
+			return err
+		}
+		v.p, v.outside = p, false
+		return nil
+	case *KyberG2:
+		v.backend = backendOrDefault(v.backend)
+		p, err := prof.unmarshalPoint(v.backend.g2(), buf, 96)
+		if err != nil {
+			return err
+		}
+		v.p, v.outside = p, false
+		return nil
+	case *KyberGT, *mod.Int:
+		if s, ok := v.(*mod.Int); ok && (s.M == nil || s.M.Cmp(curveOrder) != 0) {
+			break
+		}
+		if prof.arkworks() {
+			buf = reverse(buf)
+		}
+		return m.UnmarshalBinary(buf)
+	}
+	return fmt.Errorf("%w: cannot unmarshal %T", ErrWrongType, m)
+}
+
+// marshalPoint encodes p in the profile.
+func (prof Profile) marshalPoint(p point) []byte {
+	c := p.compressed()
+	infinity, largest := c[0]&0x40 != 0, c[0]&0x20 != 0
+	switch {
+	case !prof.arkworks() && !prof.uncompressed():
+		return c
+	case !prof.arkworks():
+		raw := p.uncompressed()
+		if infinity {
+			raw[0] |= 0x40
+		}
+		return raw
+	case !prof.uncompressed():
+		// the x-coordinate, without the flags of the ZCash format
+		c[0] &= 0x1f
+		out := reverse(c)
+		if infinity {
+			out[len(out)-1] |= 0x40
+		} else if largest {
+			// y is lexicographically largest, i.e. y > -y
+			out[len(out)-1] |= 0x80
+		}
+		return out
+	}
+	raw := p.uncompressed()
+	half := len(raw) / 2
+	if infinity {
+		// arkworks 0.3 represents the point at infinity as (0, 1)
+		out := make([]byte, len(raw))
+		out[half] = 1
+		out[len(out)-1] |= 0x40
+		return out
+	}
+	return append(reverse(raw[:half]), reverse(raw[half:])...)
+}
+
+// unmarshalPoint decodes a point of the curve c, whose compressed encoding has size bytes.
+func (prof Profile) unmarshalPoint(c curve, buf []byte, size int) (point, error) {
+	if !prof.uncompressed() && len(buf) != size || prof.uncompressed() && len(buf) != 2*size {
+		return nil, errProfileEncoding
+	}
+	if !prof.arkworks() && !prof.uncompressed() {
+		return decodeCompressed(c, buf, size)
+	}
+	if prof.arkworks() && !prof.uncompressed() {
+		flags := buf[size-1] & 0xe0
+		if flags&0x20 != 0 || flags == 0xc0 {
+			return nil, errProfileEncoding
+		}
+		x := reverse(buf)
+		x[0] &= 0x1f
+		if flags == 0x40 {
+			if !allZero(x) {
+				return nil, errProfileEncoding
+			}
+			return c.zero(), nil
+		}
+		x[0] |= 0x80
+		if flags == 0x80 {
+			x[0] |= 0x20
+		}
+		return decodeCompressed(c, x, size)
+	}
+
+	var raw []byte
+	var infinity bool
+	if prof.arkworks() {
+		flags := buf[2*size-1] & 0xe0
+		if flags&0x20 != 0 || flags == 0xc0 {
+			return nil, errProfileEncoding
+		}
+		// the sign of y is ignored, as by arkworks
+		raw = append(reverse(buf[:size]), reverse(buf[size:])...)
+		raw[size] &= 0x1f
+		infinity = flags == 0x40
+		if infinity {
+			// arkworks 0.3 writes the point at infinity as (0, 1), and later versions as (0, 0)
+			raw[2*size-1] &^= 1
+		}
+	} else {
+		flags := buf[0] & 0xe0
+		if flags&0xa0 != 0 {
+			return nil, errProfileEncoding
+		}
+		raw = append([]byte(nil), buf...)
+		raw[0] &= 0x1f
+		infinity = flags == 0x40
+	}
+	switch {
+	case infinity && allZero(raw):
+		return c.zero(), nil
+	case infinity || allZero(raw):
+		// the backends decode (0, 0) as the point at infinity, which needs the flag
+		return nil, errProfileEncoding
+	}
+	p, err := c.fromUncompressed(raw)
+	if err != nil {
+		return nil, err
+	}
+	if !p.inSubgroup() {
+		return nil, errProfileEncoding
+	}
+	return p, nil
+}
+
+func reverse(in []byte) []byte {
+	out := make([]byte, len(in))
+	for i, b := range in {
+		out[len(in)-1-i] = b
+	}
+	return out
+}
+
+func allZero(buf []byte) bool {
+	return len(bytes.Trim(buf, "\x00")) == 0
+}
@@ -0,0 +1,138 @@
+package bls
+
+import (
+	"testing"
+
+	"github.com/drand/kyber"
+	"github.com/drand/kyber/util/random"
+	"github.com/stretchr/testify/require"
+)
+
+var profiles = []Profile{ProfileZCash, ProfileZCashUncompressed, ProfileArkworks03, ProfileArkworks03Uncompressed}
+
+// newProfileValue returns an empty element of the group of a golden vector.
+func newProfileValue(t *testing.T, b Backend, group string) kyber.Marshaling {
+	switch group {
+	case "G1":
+		return newKyberG1(b.g1().zero(), nil, nil)
+	case "G2":
+		return newKyberG2(b.g2().zero(), nil, nil)
+	case "GT":
+		return newGroupGT(b).Point()
+	case "Scalar":
+		return NewKyberScalar()
+	}
+	t.Fatalf("unknown group %s", group)
+	return nil
+}
+
+func TestProfileVectors(t *testing.T) {
+	var vectors []map[string]string
+	loadJSON(t, "testdata/profiles.json", &vectors)
+	require.NotEmpty(t, vectors)
+	for _, b := range backends {
+		t.Run(b.String(), func(t *testing.T) {
+			for _, v := range vectors {
+				want := newProfileValue(t, b, v["group"])
+				require.NoError(t, want.UnmarshalBinary(decodeHex(t, v["zcash"])), v["name"])
+				// the encodings of gnark-crypto, whose profiles are aliases of the ZCash ones
+				for name, prof := range map[string]Profile{"gnark": ProfileGnark, "gnark-uncompressed": ProfileGnarkUncompressed} {
+					buf, err := prof.Marshal(want)
+					require.NoError(t, err)
+					require.Equal(t, decodeHex(t, v[name]), buf, "%s %s in %s", v["group"], v["name"], name)
+				}
+				for _, prof := range profiles {
+					enc := decodeHex(t, v[prof.String()])
+					buf, err := prof.Marshal(want)
+					require.NoError(t, err)
+					require.Equal(t, enc, buf, "%s %s in %s", v["group"], v["name"], prof)
+
+					got := newProfileValue(t, b, v["group"])
+					require.NoError(t, prof.Unmarshal(got, enc), "%s %s in %s", v["group"], v["name"], prof)
+					buf, err = got.MarshalBinary()
+					require.NoError(t, err)
+					require.Equal(t, decodeHex(t, v["zcash"]), buf, "%s %s in %s", v["group"], v["name"], prof)
+				}
+			}
+		})
+	}
+}
+
+func TestProfileRoundTrip(t *testing.T) {
+	suite := NewBLS12381Suite()
+	for _, prof := range profiles {
+		for _, m := range []kyber.Marshaling{
+			suite.G1().Point().Pick(random.New()),
+			suite.G2().Point().Pick(random.New()),
+			suite.G1().Point().Null(),
+			suite.G2().Point().Null(),
+			suite.G1().Scalar().Pick(random.New()),
+		} {
+			buf, err := prof.Marshal(m)
+			require.NoError(t, err)
+			out := newProfileValue(t, BackendKilic, map[int]string{48: "G1", 96: "G2", 32: "Scalar"}[m.MarshalSize()])
+			require.NoError(t, prof.Unmarshal(out, buf), prof.String())
+			require.Equal(t, m.String(), out.String(), prof.String())
+		}
+	}
+}
+
+func TestProfileErrors(t *testing.T) {
+	g1 := NullKyberG1().Base().(*KyberG1)
+	gen, err := ProfileArkworks03.Marshal(g1)
+	require.NoError(t, err)
+	genUncompressed, err := ProfileArkworks03Uncompressed.Marshal(g1)
+	require.NoError(t, err)
+	withLast := func(buf []byte, flags byte) []byte {
+		buf = append([]byte(nil), buf...)
+		buf[len(buf)-1] = buf[len(buf)-1]&0x1f | flags
+		return buf
+	}
+
+	// arkworks 0.4 writes the point at infinity as (0, 0), which is accepted
+	infinity := withLast(make([]byte, 96), 0x40)
+	require.NoError(t, ProfileArkworks03Uncompressed.Unmarshal(NullKyberG1(), infinity))
+	// arkworks ignores the sign of y of uncompressed points
+	require.NoError(t, ProfileArkworks03Uncompressed.Unmarshal(NullKyberG1(), withLast(genUncompressed, 0x80)))
+
+	for _, tc := range []struct {
+		name string
+		prof Profile
+		buf  []byte
+	}{
+		{"both arkworks flags", ProfileArkworks03, withLast(gen, 0xc0)},
+		{"arkworks x too large", ProfileArkworks03, withLast(gen, 0x20)},
+		{"arkworks infinity with x", ProfileArkworks03, withLast(gen, 0x40)},
+		{"arkworks infinity with y", ProfileArkworks03Uncompressed, withLast(genUncompressed, 0x40)},
+		{"arkworks (0, 0) without flag", ProfileArkworks03Uncompressed, make([]byte, 96)},
+		{"arkworks wrong length", ProfileArkworks03, gen[:47]},
+		{"zcash (0, 0) without flag", ProfileZCashUncompressed, make([]byte, 96)},
+		{"zcash compression flag", ProfileZCashUncompressed, append([]byte{0x80}, make([]byte, 95)...)},
+		{"zcash sort flag", ProfileZCashUncompressed, append([]byte{0x20}, make([]byte, 95)...)},
+		{"zcash compressed length", ProfileZCashUncompressed, make([]byte, 48)},
+		{"unknown profile", Profile(42), gen},
+	} {
+		require.Error(t, tc.prof.Unmarshal(NullKyberG1(), tc.buf), tc.name)
+	}
+
+	// uncompressed points are checked to be in the subgroup
+	var corpus negativeCorpus
+	loadJSON(t, "testdata/negative/g1.json", &corpus)
+	var outside []byte
+	for _, tc := range corpus.Tests {
+		if tc.Point != "" && tc.Result == "invalid" {
+			outside = decodeHex(t, tc.Point)
+			break
+		}
+	}
+	require.NotNil(t, outside)
+	require.Error(t, ProfileZCashUncompressed.Unmarshal(NullKyberG1(), outside))
+	require.Error(t, ProfileArkworks03Uncompressed.Unmarshal(NullKyberG1(), append(reverse(outside[:48]), reverse(outside[48:])...)))
+
+	_, err = Profile(42).Marshal(g1)
+	require.Error(t, err)
+	_, err = ProfileArkworks03.Marshal(nil)
+	require.ErrorIs(t, err, ErrWrongType)
+	require.ErrorIs(t, ProfileArkworks03.Unmarshal(nil, gen), ErrWrongType)
+	require.Equal(t, "Profile(42)", Profile(42).String())
+}
@@ -0,0 +1,293 @@
+// Command profiles writes the golden vectors of the serialization profiles, testdata/profiles.json, to the
+// current directory. The ZCash encodings are computed with github.com/kilic/bls12-381 and the gnark encodings
+// with github.com/consensys/gnark-crypto. The arkworks 0.3 encodings are written here from the coordinates
+// computed by gnark-crypto, following CanonicalSerialize of ark-serialize and ark-ec 0.3.
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"os"
+	"slices"
+
+	gnark "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	kilic "github.com/kilic/bls12-381"
+)
+
+type vector struct {
+	Group                  string `json:"group"`
+	Name                   string `json:"name"`
+	ZCash                  string `json:"zcash"`
+	ZCashUncompressed      string `json:"zcash-uncompressed"`
+	Arkworks03             string `json:"arkworks-0.3"`
+	Arkworks03Uncompressed string `json:"arkworks-0.3-uncompressed"`
+	Gnark                  string `json:"gnark"`
+	GnarkUncompressed      string `json:"gnark-uncompressed"`
+}
+
+var (
+	domainG1 = []byte("BLS_SIG_BLS12381G1_XMD:SHA-256_SSWU_RO_NUL_")
+	domainG2 = []byte("BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_NUL_")
+	msg      = []byte("abc")
+)
+
+// flags of the last byte in arkworks 0.3
+const (
+	arkPositiveY = 0x80
+	arkInfinity  = 0x40
+)
+
+func main() {
+	var vectors []vector
+	vectors = append(vectors, g1Vectors()...)
+	vectors = append(vectors, g2Vectors()...)
+	vectors = append(vectors, gtVectors()...)
+	vectors = append(vectors, scalarVectors()...)
+
+	f, err := os.Create("profiles.json")
+	if err != nil {
+		panic(err)
+	}
+	defer f.Close()
+	encoder := json.NewEncoder(f)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(vectors); err != nil {
+		panic(err)
+	}
+}
+
+func g1Vectors() []vector {
+	g := kilic.NewG1()
+	kHash, err := g.HashToCurve(msg, domainG1)
+	if err != nil {
+		panic(err)
+	}
+	_, _, gen, _ := gnark.Generators()
+	gHash, err := gnark.HashToG1(msg, domainG1)
+	if err != nil {
+		panic(err)
+	}
+	var gDouble, gNeg gnark.G1Affine
+	gDouble.Double(&gen)
+	gNeg.Neg(&gen)
+
+	var vectors []vector
+	for _, tc := range []struct {
+		name string
+		k    *kilic.PointG1
+		g    gnark.G1Affine
+	}{
+		{"generator", g.One(), gen},
+		{"infinity", g.Zero(), gnark.G1Affine{}},
+		{"double", g.Double(g.New(), g.One()), gDouble},
+		{"negation", g.Neg(g.New(), g.One()), gNeg},
+		{"hash", kHash, gHash},
+	} {
+		compressed, uncompressed := tc.g.Bytes(), tc.g.RawBytes()
+		vectors = append(vectors, vector{
+			Group:                  "G1",
+			Name:                   tc.name,
+			ZCash:                  hex.EncodeToString(g.ToCompressed(tc.k)),
+			ZCashUncompressed:      hex.EncodeToString(g.ToUncompressed(tc.k)),
+			Arkworks03:             hex.EncodeToString(arkG1(&tc.g, false)),
+			Arkworks03Uncompressed: hex.EncodeToString(arkG1(&tc.g, true)),
+			Gnark:                  hex.EncodeToString(compressed[:]),
+			GnarkUncompressed:      hex.EncodeToString(uncompressed[:]),
+		})
+	}
+	return vectors
+}
+
+func g2Vectors() []vector {
+	g := kilic.NewG2()
+	kHash, err := g.HashToCurve(msg, domainG2)
+	if err != nil {
+		panic(err)
+	}
+	_, _, _, gen := gnark.Generators()
+	gHash, err := gnark.HashToG2(msg, domainG2)
+	if err != nil {
+		panic(err)
+	}
+	var gDouble, gNeg gnark.G2Affine
+	gDouble.Double(&gen)
+	gNeg.Neg(&gen)
+
+	var vectors []vector
+	for _, tc := range []struct {
+		name string
+		k    *kilic.PointG2
+		g    gnark.G2Affine
+	}{
+		{"generator", g.One(), gen},
+		{"infinity", g.Zero(), gnark.G2Affine{}},
+		{"double", g.Double(g.New(), g.One()), gDouble},
+		{"negation", g.Neg(g.New(), g.One()), gNeg},
+		{"hash", kHash, gHash},
+	} {
+		compressed, uncompressed := tc.g.Bytes(), tc.g.RawBytes()
+		vectors = append(vectors, vector{
+			Group:                  "G2",
+			Name:                   tc.name,
+			ZCash:                  hex.EncodeToString(g.ToCompressed(tc.k)),
+			ZCashUncompressed:      hex.EncodeToString(g.ToUncompressed(tc.k)),
+			Arkworks03:             hex.EncodeToString(arkG2(&tc.g, false)),
+			Arkworks03Uncompressed: hex.EncodeToString(arkG2(&tc.g, true)),
+			Gnark:                  hex.EncodeToString(compressed[:]),
+			GnarkUncompressed:      hex.EncodeToString(uncompressed[:]),
+		})
+	}
+	return vectors
+}
+
+func gtVectors() []vector {
+	gt := kilic.NewGT()
+	kPairing := kilic.NewEngine().AddPair(kilic.NewG1().One(), kilic.NewG2().One()).Result()
+	_, _, g1, g2 := gnark.Generators()
+	gPairing, err := gnark.Pair([]gnark.G1Affine{g1}, []gnark.G2Affine{g2})
+	if err != nil {
+		panic(err)
+	}
+	var gOne gnark.GT
+	gOne.SetOne()
+
+	var vectors []vector
+	for _, tc := range []struct {
+		name string
+		k    *kilic.E
+		g    gnark.GT
+	}{
+		{"pairing", kPairing, gPairing},
+		{"one", gt.New().One(), gOne},
+	} {
+		zcash, ark, gnark := hex.EncodeToString(gt.ToBytes(tc.k)), hex.EncodeToString(arkGT(&tc.g)), hex.EncodeToString(tc.g.Marshal())
+		vectors = append(vectors, vector{
+			Group:                  "GT",
+			Name:                   tc.name,
+			ZCash:                  zcash,
+			ZCashUncompressed:      zcash,
+			Arkworks03:             ark,
+			Arkworks03Uncompressed: ark,
+			Gnark:                  gnark,
+			GnarkUncompressed:      gnark,
+		})
+	}
+	return vectors
+}
+
+func scalarVectors() []vector {
+	order := fr.Modulus()
+	random, _ := new(big.Int).SetString("2a7c7d4f5ad8d4f0e4c1b9b3d6e58c2f0a1e4c7b9d3f5a6e8c0b2d4f6a8c0e1f", 16)
+
+	var vectors []vector
+	for _, tc := range []struct {
+		name string
+		v    *big.Int
+	}{
+		{"zero", big.NewInt(0)},
+		{"one", big.NewInt(1)},
+		{"order-1", new(big.Int).Sub(order, big.NewInt(1))},
+		{"random", random},
+	} {
+		var e fr.Element
+		e.SetBigInt(tc.v)
+		zcash := hex.EncodeToString(tc.v.FillBytes(make([]byte, fr.Bytes)))
+		b := e.Bytes()
+		ark := hex.EncodeToString(reversed(b[:]))
+		gnark := hex.EncodeToString(e.Marshal())
+		vectors = append(vectors, vector{
+			Group:                  "Scalar",
+			Name:                   tc.name,
+			ZCash:                  zcash,
+			ZCashUncompressed:      zcash,
+			Arkworks03:             ark,
+			Arkworks03Uncompressed: ark,
+			Gnark:                  gnark,
+			GnarkUncompressed:      gnark,
+		})
+	}
+	return vectors
+}
+
+// arkFp is the little-endian encoding of e.
+func arkFp(e *fp.Element) []byte {
+	b := e.Bytes()
+	return reversed(b[:])
+}
+
+// arkFp2 is c0 || c1.
+func arkFp2(e *gnark.E2) []byte {
+	return append(arkFp(&e.A0), arkFp(&e.A1)...)
+}
+
+// arkGT is c0.c0.c0 || c0.c0.c1 || c0.c1.c0 || ... || c1.c2.c1.
+func arkGT(e *gnark.GT) []byte {
+	var out []byte
+	for _, e6 := range []gnark.E6{e.C0, e.C1} {
+		for _, e2 := range []gnark.E2{e6.B0, e6.B1, e6.B2} {
+			out = append(out, arkFp2(&e2)...)
+		}
+	}
+	return out
+}
+
+// arkGreater reports whether y > -y for an element of Fp.
+func arkGreater(y *fp.Element) bool {
+	var neg fp.Element
+	neg.Neg(y)
+	return y.BigInt(new(big.Int)).Cmp(neg.BigInt(new(big.Int))) > 0
+}
+
+// arkGreater2 reports whether y > -y for an element of Fp2, ordered by c1 then c0.
+func arkGreater2(y *gnark.E2) bool {
+	if !y.A1.IsZero() {
+		return arkGreater(&y.A1)
+	}
+	return arkGreater(&y.A0)
+}
+
+// arkG1 is x, with the flags of y in the last byte, or x || y with the flag of the point at infinity, which
+// arkworks 0.3 represents as (0, 1).
+func arkG1(p *gnark.G1Affine, uncompressed bool) []byte {
+	x, y, flags := p.X, p.Y, byte(0)
+	switch {
+	case p.IsInfinity():
+		y.SetOne()
+		flags = arkInfinity
+	case !uncompressed && arkGreater(&p.Y):
+		flags = arkPositiveY
+	}
+	out := arkFp(&x)
+	if uncompressed {
+		out = append(out, arkFp(&y)...)
+	}
+	out[len(out)-1] |= flags
+	return out
+}
+
+// arkG2 is arkG1 for points with coordinates in Fp2.
+func arkG2(p *gnark.G2Affine, uncompressed bool) []byte {
+	x, y, flags := p.X, p.Y, byte(0)
+	switch {
+	case p.IsInfinity():
+		y.SetOne()
+		flags = arkInfinity
+	case !uncompressed && arkGreater2(&p.Y):
+		flags = arkPositiveY
+	}
+	out := arkFp2(&x)
+	if uncompressed {
+		out = append(out, arkFp2(&y)...)
+	}
+	out[len(out)-1] |= flags
+	return out
+}
+
+func reversed(b []byte) []byte {
+	b = slices.Clone(b)
+	slices.Reverse(b)
+	return b
+}
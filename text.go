@@ -0,0 +1,200 @@
+package bls
+
+import (
+	"encoding"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/drand/kyber"
+)
+
+// The text encoding of points and scalars is the hexadecimal of MarshalBinary, and their JSON encoding is that
+// text as a JSON string. When decoding, a "0x" prefix is accepted. Like MarshalBinary, the text encoding of a
+// point does not hold its DST, see MarshalWithDomain.
+
+var errParsePoint = errors.New("bls12-381: cannot parse point")
+
+// decodeText returns the bytes encoded by MarshalText.
+func decodeText(text []byte) ([]byte, error) {
+	s := string(text)
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		s = s[2:]
+	}
+	return hex.DecodeString(s)
+}
+
+// marshalJSON returns the text m as a JSON string.
+func marshalJSON(m interface{ MarshalText() ([]byte, error) }) ([]byte, error) {
+	text, err := m.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+// unmarshalJSON decodes a JSON string with u.
+func unmarshalJSON(u interface{ UnmarshalText([]byte) error }, data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	return u.UnmarshalText([]byte(s))
+}
+
+// MarshalText implements encoding.TextMarshaler, returning the hexadecimal of the compressed point.
+func (k *KyberG1) MarshalText() ([]byte, error) {
+	buf, err := k.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(hex.EncodeToString(buf)), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, decoding the output of MarshalText.
+func (k *KyberG1) UnmarshalText(text []byte) error {
+	buf, err := decodeText(text)
+	if err != nil {
+		return err
+	}
+	return k.UnmarshalBinary(buf)
+}
+
+// MarshalJSON implements json.Marshaler, returning the output of MarshalText as a JSON string.
+func (k *KyberG1) MarshalJSON() ([]byte, error) {
+	return marshalJSON(k)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, decoding the output of MarshalJSON.
+func (k *KyberG1) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(k, data)
+}
+
+// MarshalText implements encoding.TextMarshaler, returning the hexadecimal of the compressed point.
+func (k *KyberG2) MarshalText() ([]byte, error) {
+	buf, err := k.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(hex.EncodeToString(buf)), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, decoding the output of MarshalText.
+func (k *KyberG2) UnmarshalText(text []byte) error {
+	buf, err := decodeText(text)
+	if err != nil {
+		return err
+	}
+	return k.UnmarshalBinary(buf)
+}
+
+// MarshalJSON implements json.Marshaler, returning the output of MarshalText as a JSON string.
+func (k *KyberG2) MarshalJSON() ([]byte, error) {
+	return marshalJSON(k)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, decoding the output of MarshalJSON.
+func (k *KyberG2) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(k, data)
+}
+
+// MarshalText implements encoding.TextMarshaler, returning the hexadecimal of the element.
+func (k *KyberGT) MarshalText() ([]byte, error) {
+	buf, err := k.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(hex.EncodeToString(buf)), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, decoding the output of MarshalText.
+func (k *KyberGT) UnmarshalText(text []byte) error {
+	buf, err := decodeText(text)
+	if err != nil {
+		return err
+	}
+	return k.UnmarshalBinary(buf)
+}
+
+// MarshalJSON implements json.Marshaler, returning the output of MarshalText as a JSON string.
+func (k *KyberGT) MarshalJSON() ([]byte, error) {
+	return marshalJSON(k)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, decoding the output of MarshalJSON.
+func (k *KyberGT) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(k, data)
+}
+
+// TextScalar wraps a scalar to give it the text and JSON encodings of the points, e.g. as a field of a
+// struct encoded to JSON. A nil Scalar is set to a new scalar when decoding.
+type TextScalar struct {
+	kyber.Scalar
+}
+
+// MarshalText implements encoding.TextMarshaler, returning the hexadecimal of the 32 bytes big-endian scalar.
+func (s TextScalar) MarshalText() ([]byte, error) {
+	buf, err := s.Scalar.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(hex.EncodeToString(buf)), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, decoding the output of MarshalText.
+func (s *TextScalar) UnmarshalText(text []byte) error {
+	buf, err := decodeText(text)
+	if err != nil {
+		return err
+	}
+	if s.Scalar == nil {
+		s.Scalar = NewKyberScalar()
+	}
+	return s.Scalar.UnmarshalBinary(buf)
+}
+
+// MarshalJSON implements json.Marshaler, returning the output of MarshalText as a JSON string.
+func (s TextScalar) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, decoding the output of MarshalJSON.
+func (s *TextScalar) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(s, data)
+}
+
+// ParsePoint decodes the output of the String method of KyberG1, KyberG2 and KyberGT, e.g.
+// "bls12-381.G1: 97f1d3...", as well as their text encoding, the group being given by its length. The points
+// have the default DST and backend.
+func ParsePoint(s string) (kyber.Point, error) {
+	candidates := []struct {
+		prefix string
+		point  kyber.Point
+	}{
+		{"bls12-381.G1: ", NullKyberG1()},
+		{"bls12-381.G2: ", NullKyberG2()},
+		{"bls12-381.GT: ", newEmptyGT()},
+	}
+	for _, c := range candidates {
+		if strings.HasPrefix(s, c.prefix) {
+			if err := c.point.(encoding.TextUnmarshaler).UnmarshalText([]byte(s[len(c.prefix):])); err != nil {
+				return nil, err
+			}
+			return c.point, nil
+		}
+	}
+	buf, err := decodeText([]byte(s))
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range candidates {
+		if len(buf) == c.point.MarshalSize() {
+			if err := c.point.UnmarshalBinary(buf); err != nil {
+				return nil, err
+			}
+			return c.point, nil
+		}
+	}
+	return nil, errParsePoint
+}
@@ -0,0 +1,81 @@
+package bls
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/drand/kyber"
+	"github.com/drand/kyber/util/random"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTextMarshalling(t *testing.T) {
+	suite := NewBLS12381Suite()
+	for _, p := range []kyber.Point{
+		suite.G1().Point().Pick(random.New()),
+		suite.G2().Point().Pick(random.New()),
+		suite.Pair(suite.G1().Point().Pick(random.New()), suite.G2().Point().Base()),
+	} {
+		buf, err := p.MarshalBinary()
+		require.NoError(t, err)
+		text, err := p.(interface{ MarshalText() ([]byte, error) }).MarshalText()
+		require.NoError(t, err)
+		require.Equal(t, hex.EncodeToString(buf), string(text))
+
+		// String can be parsed back, as well as the text with or without 0x
+		for _, s := range []string{p.String(), string(text), "0x" + string(text)} {
+			q, err := ParsePoint(s)
+			require.NoError(t, err)
+			require.IsType(t, p, q)
+			require.True(t, q.Equal(p), s)
+		}
+	}
+	for _, s := range []string{"", "bls12-381.G1: ", "bls12-381.G2: 00", "0102", "zz", "bls12-381.G1: " + hex.EncodeToString(make([]byte, 48))} {
+		_, err := ParsePoint(s)
+		require.Error(t, err, s)
+	}
+}
+
+func TestJSONMarshalling(t *testing.T) {
+	suite := NewBLS12381Suite()
+	type message struct {
+		Key       *KyberG1
+		Signature *KyberG2
+		Pairing   *KyberGT
+		Secret    TextScalar
+		Keys      []*KyberG1
+	}
+	in := message{
+		Key:       suite.G1().Point().Pick(random.New()).(*KyberG1),
+		Signature: suite.G2().Point().Pick(random.New()).(*KyberG2),
+		Secret:    TextScalar{suite.G1().Scalar().Pick(random.New())},
+		Keys:      []*KyberG1{NullKyberG1(), NullKyberG1().Base().(*KyberG1)},
+	}
+	in.Pairing = suite.Pair(in.Key, in.Signature).(*KyberGT)
+	data, err := json.Marshal(in)
+	require.NoError(t, err)
+
+	var fields map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &fields))
+	require.Equal(t, in.Key.String()[len("bls12-381.G1: "):], fields["Key"])
+	secret, err := in.Secret.MarshalBinary()
+	require.NoError(t, err)
+	require.Equal(t, hex.EncodeToString(secret), fields["Secret"])
+	require.Equal(t, []interface{}{"c0" + hex.EncodeToString(make([]byte, 47)), in.Keys[1].String()[len("bls12-381.G1: "):]}, fields["Keys"])
+
+	var out message
+	require.NoError(t, json.Unmarshal(data, &out))
+	require.True(t, out.Key.Equal(in.Key))
+	require.True(t, out.Signature.Equal(in.Signature))
+	require.True(t, out.Pairing.Equal(in.Pairing))
+	require.True(t, out.Secret.Equal(in.Secret.Scalar))
+	require.True(t, out.Keys[0].Equal(in.Keys[0]))
+	require.True(t, out.Keys[1].Equal(in.Keys[1]))
+	// the decoded points are usable
+	require.True(t, suite.G1().Point().Add(out.Key, out.Keys[1]).Equal(suite.G1().Point().Add(in.Key, in.Keys[1])))
+
+	require.Error(t, json.Unmarshal([]byte(`{"Key": "00"}`), &out))
+	require.Error(t, json.Unmarshal([]byte(`{"Key": 1}`), &out))
+	require.Error(t, json.Unmarshal([]byte(`{"Secret": "`+hex.EncodeToString(curveOrder.Bytes())+`"}`), &out))
+}
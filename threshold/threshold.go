@@ -0,0 +1,370 @@
+// Package threshold implements the threshold BLS signatures of kyber's sign/tbls, specialized for BLS12-381.
+//
+// The partial signatures have the encoding of sign/tbls, the 2-byte big-endian index of the share followed
+// by the BLS signature of the message with the share, so that both packages interoperate. Scheme implements
+// sign.ThresholdScheme, with the following differences:
+//
+//   - the partial signatures are verified together, with a random linear combination checked by a single
+//     multi-pairing, and the misbehaving ones are found by bisection when the batch does not verify;
+//   - the public key shares of the batch are combined with one multi-scalar multiplication over the
+//     coefficients of the public polynomial, instead of evaluating it at each index;
+//   - the signature is recovered with one multi-scalar multiplication by the Lagrange coefficients of the
+//     signers, which Signers precomputes for a given set of signers.
+package threshold
+
+import (
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/drand/kyber"
+	bls "github.com/drand/kyber-bls12381"
+	"github.com/drand/kyber-bls12381/poly"
+	"github.com/drand/kyber/pairing"
+	"github.com/drand/kyber/share"
+	"github.com/drand/kyber/sign"
+	"github.com/drand/kyber/util/random"
+)
+
+// ErrInvalidSignature is returned when a signature does not verify.
+var ErrInvalidSignature = errors.New("threshold: invalid signature")
+
+var (
+	errDuplicate = errors.New("threshold: duplicate signer")
+	errIndex     = errors.New("threshold: invalid signer index")
+	errNotEnough = errors.New("threshold: not enough valid partial signatures")
+)
+
+var _ sign.ThresholdScheme = (*Scheme)(nil)
+
+// Scheme is the threshold BLS scheme with its signatures in one group of BLS12-381, and its public keys in
+// the other.
+type Scheme struct {
+	suite pairing.Suite
+	// the groups of the signatures and of the public keys
+	sigs, keys kyber.Group
+	// onG1 is set when the signatures are on G1
+	onG1 bool
+}
+
+// NewSchemeOnG1 returns the scheme with its signatures on G1 and its public keys on G2, as
+// tbls.NewThresholdSchemeOnG1.
+func NewSchemeOnG1(suite pairing.Suite) *Scheme {
+	return &Scheme{suite: suite, sigs: suite.G1(), keys: suite.G2(), onG1: true}
+}
+
+// NewSchemeOnG2 returns the scheme with its signatures on G2 and its public keys on G1, as
+// tbls.NewThresholdSchemeOnG2.
+func NewSchemeOnG2(suite pairing.Suite) *Scheme {
+	return &Scheme{suite: suite, sigs: suite.G2(), keys: suite.G1()}
+}
+
+// partial is a decoded partial signature.
+type partial struct {
+	// pos is the position of the partial signature in its slice
+	pos, index int
+	sig        kyber.Point
+}
+
+// Sign returns the partial signature of msg with the private share.
+func (s *Scheme) Sign(private *share.PriShare, msg []byte) ([]byte, error) {
+	if private.I < 0 || private.I > 0xffff {
+		return nil, errIndex
+	}
+	h, err := s.hash(msg)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := h.Mul(private.V, h).MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	buf := binary.BigEndian.AppendUint16(make([]byte, 0, 2+len(sig)), uint16(private.I))
+	return append(buf, sig...), nil
+}
+
+// IndexOf returns the index of the share of the partial signature.
+func (s *Scheme) IndexOf(sig []byte) (int, error) {
+	if len(sig) != 2+s.sigs.PointLen() {
+		return -1, fmt.Errorf("threshold: invalid partial signature length %d", len(sig))
+	}
+	return int(binary.BigEndian.Uint16(sig)), nil
+}
+
+// VerifyPartial checks the partial signature of msg, for the public polynomial of the shared key.
+func (s *Scheme) VerifyPartial(public *share.PubPoly, msg, sig []byte) error {
+	invalid, err := s.VerifyPartials(public, msg, [][]byte{sig}, random.New())
+	if err != nil {
+		return err
+	}
+	if len(invalid) > 0 {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// VerifyPartials checks the partial signatures of msg, for the public polynomial of the shared key, and
+// returns the positions in sigs of the invalid ones, which include the partial signatures that cannot be
+// decoded. The valid partial signatures are combined with random scalars from rand
+// into a single pairing check, and bisected if it fails.
+func (s *Scheme) VerifyPartials(public *share.PubPoly, msg []byte, sigs [][]byte, rand cipher.Stream) ([]int, error) {
+	h, err := s.hash(msg)
+	if err != nil {
+		return nil, err
+	}
+	ps, invalid := s.decode(sigs, -1)
+	bad, err := s.verify(public, h, ps, rand)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range bad {
+		invalid = append(invalid, p.pos)
+	}
+	sort.Ints(invalid)
+	return invalid, nil
+}
+
+// VerifyRecovered checks the signature of msg for the shared public key.
+func (s *Scheme) VerifyRecovered(public kyber.Point, msg, sig []byte) error {
+	h, err := s.hash(msg)
+	if err != nil {
+		return err
+	}
+	p := s.sigs.Point()
+	if err := p.UnmarshalBinary(sig); err != nil {
+		return err
+	}
+	if !s.check(h, p, public) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// Recover returns the signature of msg from t valid partial signatures of the n signers. The partial
+// signatures are verified t at a time, the invalid ones being replaced by the next ones of sigs, and the
+// signature is their combination by the Lagrange coefficients of their signers.
+func (s *Scheme) Recover(public *share.PubPoly, msg []byte, sigs [][]byte, t, n int) ([]byte, error) {
+	if t <= 0 {
+		return nil, errNotEnough
+	}
+	h, err := s.hash(msg)
+	if err != nil {
+		return nil, err
+	}
+	pending, _ := s.decode(sigs, n)
+	var valid []partial
+	// the signers of valid, a repeated index being only dropped once verified so that an invalid partial
+	// signature cannot hide a valid one
+	seen := make(map[int]bool)
+	for len(valid) < t && len(pending) > 0 {
+		k := min(t-len(valid), len(pending))
+		batch := pending[:k]
+		pending = pending[k:]
+		bad, err := s.verify(public, h, batch, random.New())
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range without(batch, bad) {
+			if !seen[p.index] && len(valid) < t {
+				seen[p.index] = true
+				valid = append(valid, p)
+			}
+		}
+	}
+	if len(valid) < t {
+		return nil, errNotEnough
+	}
+	indices := make([]int, t)
+	points := make([]kyber.Point, t)
+	for i, p := range valid {
+		indices[i], points[i] = p.index, p.sig
+	}
+	signers, err := s.NewSigners(indices)
+	if err != nil {
+		return nil, err
+	}
+	return bls.LinearCombination(s.sigs.Point(), points, signers.coeffs).MarshalBinary()
+}
+
+// decode returns the partial signatures of sigs which can be decoded, with an index lower than n if n is not
+// negative, along with the positions of the others.
+func (s *Scheme) decode(sigs [][]byte, n int) ([]partial, []int) {
+	var ps []partial
+	var invalid []int
+	for pos, buf := range sigs {
+		i, err := s.IndexOf(buf)
+		if err != nil || (n >= 0 && i >= n) {
+			invalid = append(invalid, pos)
+			continue
+		}
+		p := s.sigs.Point()
+		if err := p.UnmarshalBinary(buf[2:]); err != nil {
+			invalid = append(invalid, pos)
+			continue
+		}
+		ps = append(ps, partial{pos: pos, index: i, sig: p})
+	}
+	return ps, invalid
+}
+
+// verify returns the invalid partial signatures of ps, for the hash h of the message.
+func (s *Scheme) verify(public *share.PubPoly, h kyber.Point, ps []partial, rand cipher.Stream) ([]partial, error) {
+	if len(ps) == 0 {
+		return nil, nil
+	}
+	_, commits := public.Info()
+	if len(commits) == 0 {
+		return nil, errors.New("threshold: empty public polynomial")
+	}
+	rs := make([]kyber.Scalar, len(ps))
+	for i := range rs {
+		rs[i] = s.sigs.Scalar().Pick(rand)
+	}
+	return s.bisect(commits, h, ps, rs), nil
+}
+
+// bisect returns the invalid partial signatures of ps, checking them together with the random scalars rs and
+// splitting them in halves until the invalid ones are isolated.
+func (s *Scheme) bisect(commits []kyber.Point, h kyber.Point, ps []partial, rs []kyber.Scalar) []partial {
+	sigs := make([]kyber.Point, len(ps))
+	// Σ r_i X_i = Σ_j (Σ_i r_i x_i^j) C_j, x_i = index + 1 being the evaluation point of the share
+	coeffs := make([]kyber.Scalar, len(commits))
+	for j := range coeffs {
+		coeffs[j] = s.keys.Scalar().Zero()
+	}
+	for i, p := range ps {
+		sigs[i] = p.sig
+		x := s.keys.Scalar().SetInt64(int64(p.index + 1))
+		xj := rs[i].Clone()
+		for j := range coeffs {
+			coeffs[j].Add(coeffs[j], xj)
+			xj.Mul(xj, x)
+		}
+	}
+	sig, public := bls.LinearCombination(s.sigs.Point(), sigs, rs), bls.LinearCombination(s.keys.Point(), commits, coeffs)
+	if s.check(h, sig, public) {
+		return nil
+	}
+	if len(ps) == 1 {
+		return ps
+	}
+	m := len(ps) / 2
+	return append(s.bisect(commits, h, ps[:m], rs[:m]), s.bisect(commits, h, ps[m:], rs[m:])...)
+}
+
+// check returns whether sig is the signature of the message of hash h for the public key.
+func (s *Scheme) check(h, sig, public kyber.Point) bool {
+	neg := h.Clone().Neg(h)
+	// e(sig, G2) e(-H(m), X) = 1 for signatures on G1, e(G1, sig) e(X, -H(m)) = 1 for signatures on G2
+	if s.onG1 {
+		return bls.CheckPairings(s.suite, []kyber.Point{sig, neg}, []kyber.Point{s.keys.Point().Base(), public})
+	}
+	return bls.CheckPairings(s.suite, []kyber.Point{s.keys.Point().Base(), public}, []kyber.Point{sig, neg})
+}
+
+// hash returns the hash of msg to the group of the signatures, with the DST of the suite.
+func (s *Scheme) hash(msg []byte) (kyber.Point, error) {
+	h, ok := s.sigs.Point().(kyber.HashablePoint)
+	if !ok {
+		return nil, errors.New("threshold: point needs to implement hashablePoint")
+	}
+	return h.Hash(msg), nil
+}
+
+// Signers is a set of signers, with their Lagrange coefficients at zero, to recover the signatures from
+// their partial signatures with a single multi-scalar multiplication.
+type Signers struct {
+	scheme  *Scheme
+	indices []int
+	// coeffs[i] is the Lagrange coefficient of indices[i]
+	coeffs []kyber.Scalar
+}
+
+// NewSigners returns the set of signers of the share indices, which are sorted. It returns an error if an
+// index is negative or repeated.
+func (s *Scheme) NewSigners(indices []int) (*Signers, error) {
+	if len(indices) == 0 {
+		return nil, errNotEnough
+	}
+	indices = append([]int(nil), indices...)
+	sort.Ints(indices)
+	xs := make([]kyber.Scalar, len(indices))
+	for i, idx := range indices {
+		if idx < 0 {
+			return nil, errIndex
+		}
+		if i > 0 && idx == indices[i-1] {
+			return nil, errDuplicate
+		}
+		xs[i] = s.sigs.Scalar().SetInt64(int64(idx + 1))
+	}
+	// λ_i = Π_{j != i} x_j / (x_j - x_i) = Π_j x_j / (x_i Π_{j != i} (x_j - x_i))
+	num := s.sigs.Scalar().One()
+	den := make([]kyber.Scalar, len(xs))
+	for i, x := range xs {
+		num.Mul(num, x)
+		den[i] = x.Clone()
+		for j, y := range xs {
+			if j != i {
+				den[i].Mul(den[i], s.sigs.Scalar().Sub(y, x))
+			}
+		}
+	}
+	inv, err := poly.BatchInvert(s.sigs, den)
+	if err != nil {
+		return nil, err
+	}
+	for i := range inv {
+		inv[i].Mul(inv[i], num)
+	}
+	return &Signers{scheme: s, indices: indices, coeffs: inv}, nil
+}
+
+// Indices returns the sorted share indices of the signers.
+func (sg *Signers) Indices() []int {
+	return append([]int(nil), sg.indices...)
+}
+
+// Recover returns the signature from the partial signatures of the signers, one for each of them in any
+// order. The partial signatures are not verified: see Scheme.VerifyPartials.
+func (sg *Signers) Recover(sigs [][]byte) ([]byte, error) {
+	if len(sigs) != len(sg.indices) {
+		return nil, fmt.Errorf("threshold: %d partial signatures for %d signers", len(sigs), len(sg.indices))
+	}
+	points := make([]kyber.Point, len(sigs))
+	for _, buf := range sigs {
+		i, err := sg.scheme.IndexOf(buf)
+		if err != nil {
+			return nil, err
+		}
+		k := sort.SearchInts(sg.indices, i)
+		if k == len(sg.indices) || sg.indices[k] != i {
+			return nil, errIndex
+		}
+		if points[k] != nil {
+			return nil, errDuplicate
+		}
+		points[k] = sg.scheme.sigs.Point()
+		if err := points[k].UnmarshalBinary(buf[2:]); err != nil {
+			return nil, err
+		}
+	}
+	return bls.LinearCombination(sg.scheme.sigs.Point(), points, sg.coeffs).MarshalBinary()
+}
+
+// without returns the partial signatures of ps which are not in bad.
+func without(ps, bad []partial) []partial {
+	drop := make(map[int]bool, len(bad))
+	for _, p := range bad {
+		drop[p.pos] = true
+	}
+	var r []partial
+	for _, p := range ps {
+		if !drop[p.pos] {
+			r = append(r, p)
+		}
+	}
+	return r
+}
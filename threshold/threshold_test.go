@@ -0,0 +1,191 @@
+package threshold
+
+import (
+	"testing"
+
+	bls "github.com/drand/kyber-bls12381"
+	"github.com/drand/kyber/pairing"
+	"github.com/drand/kyber/share"
+	"github.com/drand/kyber/sign"
+	"github.com/drand/kyber/sign/tbls"
+	"github.com/drand/kyber/sign/test"
+	"github.com/drand/kyber/util/random"
+	"github.com/stretchr/testify/require"
+)
+
+var backends = []bls.Backend{bls.BackendKilic, bls.BackendGnark}
+
+var msg = []byte("threshold")
+
+// schemes returns the schemes of the package along with the ones of kyber's sign/tbls.
+func schemes(suite pairing.Suite) map[string]struct {
+	s    *Scheme
+	tbls sign.ThresholdScheme
+} {
+	return map[string]struct {
+		s    *Scheme
+		tbls sign.ThresholdScheme
+	}{
+		"G1": {NewSchemeOnG1(suite), tbls.NewThresholdSchemeOnG1(suite)},
+		"G2": {NewSchemeOnG2(suite), tbls.NewThresholdSchemeOnG2(suite)},
+	}
+}
+
+// deal returns the public polynomial of a secret shared among n signers with threshold t, with their
+// partial signatures of msg.
+func deal(t *testing.T, s *Scheme, th, n int) (*share.PriPoly, *share.PubPoly, [][]byte) {
+	pri := share.NewPriPoly(s.keys, th, s.keys.Scalar().Pick(random.New()), random.New())
+	var sigs [][]byte
+	for _, x := range pri.Shares(n) {
+		sig, err := s.Sign(x, msg)
+		require.NoError(t, err)
+		sigs = append(sigs, sig)
+	}
+	return pri, pri.Commit(s.keys.Point().Base()), sigs
+}
+
+func TestKyber(t *testing.T) {
+	for _, b := range backends {
+		for name, s := range schemes(bls.NewBLS12381Suite(bls.WithBackend(b))) {
+			t.Run(b.String()+"/"+name, func(t *testing.T) {
+				test.ThresholdTest(t, s.s.keys, s.s)
+			})
+		}
+	}
+}
+
+func TestRecover(t *testing.T) {
+	for _, b := range backends {
+		suites := map[string]pairing.Suite{
+			"":        bls.NewBLS12381Suite(bls.WithBackend(b)),
+			"/strict": bls.NewBLS12381Suite(bls.WithBackend(b), bls.WithStrictDomains()),
+		}
+		for suffix, suite := range suites {
+			for name, s := range schemes(suite) {
+				t.Run(b.String()+"/"+name+suffix, func(t *testing.T) {
+					pri, pub, sigs := deal(t, s.s, 4, 7)
+					sig, err := s.s.Recover(pub, msg, sigs[2:], 4, 7)
+					require.NoError(t, err)
+					require.NoError(t, s.s.VerifyRecovered(pub.Commit(), msg, sig))
+					require.NoError(t, s.tbls.VerifyRecovered(pub.Commit(), msg, sig))
+
+					// the signature with the shared secret, as recovered by sign/tbls
+					full, err := s.s.Sign(&share.PriShare{I: 0, V: pri.Secret()}, msg)
+					require.NoError(t, err)
+					require.Equal(t, full[2:], sig)
+					expected, err := s.tbls.Recover(pub, msg, sigs, 4, 7)
+					require.NoError(t, err)
+					require.Equal(t, expected, sig)
+
+					// the partial signatures of sign/tbls
+					for _, x := range pri.Shares(7) {
+						partial, err := s.tbls.Sign(x, msg)
+						require.NoError(t, err)
+						require.Equal(t, sigs[x.I], partial)
+						require.NoError(t, s.s.VerifyPartial(pub, msg, partial))
+						require.NoError(t, s.tbls.VerifyPartial(pub, msg, sigs[x.I]))
+					}
+
+					_, err = s.s.Recover(pub, msg, sigs[:3], 4, 7)
+					require.Error(t, err)
+					require.ErrorIs(t, s.s.VerifyRecovered(pub.Commit(), []byte("other"), sig), ErrInvalidSignature)
+				})
+			}
+		}
+	}
+}
+
+func TestVerifyPartials(t *testing.T) {
+	suite := bls.NewBLS12381Suite()
+	for name, s := range schemes(suite) {
+		_, pub, sigs := deal(t, s.s, 3, 8)
+		invalid, err := s.s.VerifyPartials(pub, msg, sigs, random.New())
+		require.NoError(t, err)
+		require.Empty(t, invalid, name)
+
+		// a signature of another message, a signature for another index, a point which does not decode and
+		// a truncated partial signature
+		other, err := s.s.Sign(&share.PriShare{I: 1, V: s.s.keys.Scalar().Pick(random.New())}, []byte("other"))
+		require.NoError(t, err)
+		sigs[1] = other
+		sigs[3] = append([]byte{0, 3}, sigs[4][2:]...)
+		sigs[5] = append([]byte{0, 5}, make([]byte, s.s.sigs.PointLen())...)
+		sigs[5][2] = 0x80
+		sigs[5][3] = 0xff
+		sigs[6] = sigs[6][:len(sigs[6])-1]
+		invalid, err = s.s.VerifyPartials(pub, msg, sigs, random.New())
+		require.NoError(t, err)
+		require.Equal(t, []int{1, 3, 5, 6}, invalid, name)
+		require.ErrorIs(t, s.s.VerifyPartial(pub, msg, sigs[1]), ErrInvalidSignature)
+		require.Error(t, s.s.VerifyPartial(pub, msg, sigs[6]))
+
+		// three valid partial signatures remain
+		sig, err := s.s.Recover(pub, msg, sigs, 3, 8)
+		require.NoError(t, err)
+		require.NoError(t, s.s.VerifyRecovered(pub.Commit(), msg, sig))
+		_, err = s.s.Recover(pub, msg, sigs, 5, 8)
+		require.Error(t, err)
+	}
+}
+
+// TestRecoverDuplicate checks that an invalid partial signature does not hide a valid one of the same index.
+func TestRecoverDuplicate(t *testing.T) {
+	s := NewSchemeOnG1(bls.NewBLS12381Suite())
+	_, pub, sigs := deal(t, s, 2, 3)
+	forged := append([]byte{0, 0}, sigs[1][2:]...)
+	sig, err := s.Recover(pub, msg, [][]byte{forged, sigs[0], sigs[0], sigs[2]}, 2, 3)
+	require.NoError(t, err)
+	require.NoError(t, s.VerifyRecovered(pub.Commit(), msg, sig))
+
+	// indices of at least n
+	_, err = s.Recover(pub, msg, sigs, 2, 1)
+	require.Error(t, err)
+}
+
+func TestSigners(t *testing.T) {
+	for name, s := range schemes(bls.NewBLS12381Suite()) {
+		_, pub, sigs := deal(t, s.s, 3, 6)
+		signers, err := s.s.NewSigners([]int{5, 1, 3})
+		require.NoError(t, err)
+		require.Equal(t, []int{1, 3, 5}, signers.Indices())
+		expected, err := s.s.Recover(pub, msg, [][]byte{sigs[1], sigs[3], sigs[5]}, 3, 6)
+		require.NoError(t, err)
+		sig, err := signers.Recover([][]byte{sigs[3], sigs[5], sigs[1]})
+		require.NoError(t, err)
+		require.Equal(t, expected, sig, name)
+
+		// the coefficients sum to one, as the interpolation of a constant
+		sum := s.s.sigs.Scalar().Zero()
+		for _, c := range signers.coeffs {
+			sum.Add(sum, c)
+		}
+		require.True(t, sum.Equal(s.s.sigs.Scalar().One()))
+
+		_, err = signers.Recover([][]byte{sigs[3], sigs[5]})
+		require.Error(t, err)
+		_, err = signers.Recover([][]byte{sigs[3], sigs[5], sigs[3]})
+		require.Error(t, err)
+		_, err = signers.Recover([][]byte{sigs[3], sigs[5], sigs[0]})
+		require.Error(t, err)
+	}
+	s := NewSchemeOnG1(bls.NewBLS12381Suite())
+	for _, indices := range [][]int{nil, {0, 2, 0}, {-1, 2}} {
+		_, err := s.NewSigners(indices)
+		require.Error(t, err)
+	}
+}
+
+// TestPairingFallback checks the schemes with a suite without PairingCheck.
+func TestPairingFallback(t *testing.T) {
+	s := NewSchemeOnG2(plainSuite{bls.NewBLS12381Suite()})
+	_, pub, sigs := deal(t, s, 2, 4)
+	sigs[0] = append([]byte{0, 0}, sigs[1][2:]...)
+	sig, err := s.Recover(pub, msg, sigs, 2, 4)
+	require.NoError(t, err)
+	require.NoError(t, s.VerifyRecovered(pub.Commit(), msg, sig))
+}
+
+// plainSuite hides the PairingCheck method of the suite.
+type plainSuite struct {
+	pairing.Suite
+}
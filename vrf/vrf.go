@@ -0,0 +1,125 @@
+// Package vrf implements a verifiable random function from BLS signatures on BLS12-381, with the interface
+// of RFC 9381: the proof pi of the input alpha is the BLS signature of alpha, which is unique for a public
+// key, and the output beta is the hash of pi.
+//
+// The signatures hash alpha to the curve with the DSTs of this package, DomainG1 and DomainG2, so that the
+// proofs are not valid BLS signatures of other protocols. The output is
+//
+//	beta = SHA-256(DST || 0x03 || pi || 0x00)
+//
+// pi being the compressed encoding of the signature, as proof_to_hash of RFC 9381 with the DST as the
+// suite_string.
+package vrf
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"github.com/drand/kyber"
+	bls "github.com/drand/kyber-bls12381"
+	"github.com/drand/kyber/pairing"
+)
+
+var (
+	// DomainG1 is the DST of the hash to curve of the proofs on G1.
+	DomainG1 = []byte("BLS_VRF_BLS12381G1_XMD:SHA-256_SSWU_RO_")
+	// DomainG2 is the DST of the hash to curve of the proofs on G2.
+	DomainG2 = []byte("BLS_VRF_BLS12381G2_XMD:SHA-256_SSWU_RO_")
+)
+
+// OutputSize is the size of the outputs beta.
+const OutputSize = sha256.Size
+
+var errZero = errors.New("vrf: the secret key is zero")
+
+// Scheme is the VRF with its proofs in one group of BLS12-381, and its public keys in the other.
+type Scheme struct {
+	suite pairing.Suite
+	// the groups of the proofs and of the public keys
+	proofs, keys kyber.Group
+	// onG1 is set when the proofs are on G1
+	onG1 bool
+	dst  []byte
+}
+
+// NewSchemeOnG1 returns the VRF with its proofs on G1 and its public keys on G2, with proofs of 48 bytes.
+func NewSchemeOnG1(suite pairing.Suite) *Scheme {
+	return &Scheme{suite: suite, proofs: suite.G1(), keys: suite.G2(), onG1: true, dst: DomainG1}
+}
+
+// NewSchemeOnG2 returns the VRF with its proofs on G2 and its public keys on G1, with proofs of 96 bytes.
+func NewSchemeOnG2(suite pairing.Suite) *Scheme {
+	return &Scheme{suite: suite, proofs: suite.G2(), keys: suite.G1(), dst: DomainG2}
+}
+
+// Prove returns the proof pi of alpha for the secret key sk, along with the output beta.
+func (s *Scheme) Prove(sk kyber.Scalar, alpha []byte) (pi, beta []byte, err error) {
+	if sk.Equal(s.keys.Scalar().Zero()) {
+		return nil, nil, errZero
+	}
+	h, err := s.hash(alpha)
+	if err != nil {
+		return nil, nil, err
+	}
+	pi, err = h.Mul(sk, h).MarshalBinary()
+	if err != nil {
+		return nil, nil, err
+	}
+	return pi, s.output(pi), nil
+}
+
+// Verify checks the proof pi of alpha for the public key pk, returning the output beta if it is valid. The
+// public key must be in the prime order subgroup, as checked by its UnmarshalBinary, and not the identity.
+func (s *Scheme) Verify(pk kyber.Point, alpha, pi []byte) (beta []byte, ok bool) {
+	if pk.Equal(s.keys.Point().Null()) {
+		return nil, false
+	}
+	sig := s.proofs.Point()
+	if err := sig.UnmarshalBinary(pi); err != nil {
+		return nil, false
+	}
+	h, err := s.hash(alpha)
+	if err != nil {
+		return nil, false
+	}
+	// e(pi, G2) = e(H(alpha), pk) for proofs on G1, e(G1, pi) = e(pk, H(alpha)) for proofs on G2
+	if s.onG1 {
+		ok = s.suite.ValidatePairing(sig, s.keys.Point().Base(), h, pk)
+	} else {
+		ok = s.suite.ValidatePairing(s.keys.Point().Base(), sig, pk, h)
+	}
+	if !ok {
+		return nil, false
+	}
+	return s.output(pi), true
+}
+
+// ProofToHash returns the output beta of the proof pi, which must be the encoding of a point of the group of
+// the proofs. It does not verify the proof.
+func (s *Scheme) ProofToHash(pi []byte) ([]byte, error) {
+	if err := s.proofs.Point().UnmarshalBinary(pi); err != nil {
+		return nil, fmt.Errorf("vrf: invalid proof: %w", err)
+	}
+	return s.output(pi), nil
+}
+
+// PublicKey returns the public key of the secret key sk.
+func (s *Scheme) PublicKey(sk kyber.Scalar) kyber.Point {
+	return s.keys.Point().Mul(sk, nil)
+}
+
+// hash returns alpha hashed to the group of the proofs, with the domain of the points of the suite.
+func (s *Scheme) hash(alpha []byte) (kyber.Point, error) {
+	return bls.HashToGroup(s.proofs, alpha, s.dst)
+}
+
+// output returns the hash of the proof pi.
+func (s *Scheme) output(pi []byte) []byte {
+	h := sha256.New()
+	h.Write(s.dst)
+	h.Write([]byte{0x03})
+	h.Write(pi)
+	h.Write([]byte{0x00})
+	return h.Sum(nil)
+}
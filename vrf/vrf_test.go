@@ -0,0 +1,145 @@
+package vrf
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"testing"
+
+	bls "github.com/drand/kyber-bls12381"
+	"github.com/drand/kyber/pairing"
+	"github.com/drand/kyber/sign"
+	blssign "github.com/drand/kyber/sign/bls"
+	"github.com/drand/kyber/util/random"
+	"github.com/stretchr/testify/require"
+)
+
+var backends = []bls.Backend{bls.BackendKilic, bls.BackendGnark}
+
+func schemes(suite pairing.Suite) map[string]*Scheme {
+	return map[string]*Scheme{"G1": NewSchemeOnG1(suite), "G2": NewSchemeOnG2(suite)}
+}
+
+// vector is a known-answer vector of testdata/vectors.json, with hex-encoded fields.
+type vector struct {
+	Layout string `json:"layout"`
+	SK     string `json:"sk"`
+	PK     string `json:"pk"`
+	Alpha  string `json:"alpha"`
+	Pi     string `json:"pi"`
+	Beta   string `json:"beta"`
+}
+
+func TestVRF(t *testing.T) {
+	for _, b := range backends {
+		suites := map[string]pairing.Suite{
+			"":        bls.NewBLS12381Suite(bls.WithBackend(b)),
+			"/strict": bls.NewBLS12381Suite(bls.WithBackend(b), bls.WithStrictDomains()),
+		}
+		for suffix, suite := range suites {
+			for name, s := range schemes(suite) {
+				t.Run(b.String()+"/"+name+suffix, func(t *testing.T) {
+					sk := s.keys.Scalar().Pick(random.New())
+					pk := s.PublicKey(sk)
+					alpha := []byte("alpha")
+					pi, beta, err := s.Prove(sk, alpha)
+					require.NoError(t, err)
+					require.Len(t, pi, s.proofs.PointLen())
+					require.Len(t, beta, OutputSize)
+
+					out, ok := s.Verify(pk, alpha, pi)
+					require.True(t, ok)
+					require.Equal(t, beta, out)
+					out, err = s.ProofToHash(pi)
+					require.NoError(t, err)
+					require.Equal(t, beta, out)
+
+					// the proofs are unique
+					again, _, err := s.Prove(sk, alpha)
+					require.NoError(t, err)
+					require.Equal(t, pi, again)
+
+					_, ok = s.Verify(pk, []byte("other"), pi)
+					require.False(t, ok)
+					_, ok = s.Verify(s.keys.Point().Pick(random.New()), alpha, pi)
+					require.False(t, ok)
+					_, ok = s.Verify(s.keys.Point().Null(), alpha, pi)
+					require.False(t, ok)
+					other, _, err := s.Prove(sk, []byte("other"))
+					require.NoError(t, err)
+					_, ok = s.Verify(pk, alpha, other)
+					require.False(t, ok)
+					_, ok = s.Verify(pk, alpha, pi[1:])
+					require.False(t, ok)
+				})
+			}
+		}
+	}
+}
+
+// TestSignature checks that the proofs are the BLS signatures of kyber with the DSTs of the package.
+func TestSignature(t *testing.T) {
+	suite := bls.NewBLS12381SuiteWithDST(DomainG1, DomainG2)
+	signers := map[string]func(pairing.Suite) sign.AggregatableScheme{"G1": blssign.NewSchemeOnG1, "G2": blssign.NewSchemeOnG2}
+	for name, s := range schemes(suite) {
+		scheme := signers[name](suite)
+		sk, pk := scheme.NewKeyPair(random.New())
+		pi, _, err := s.Prove(sk, []byte("alpha"))
+		require.NoError(t, err)
+		sig, err := scheme.Sign(sk, []byte("alpha"))
+		require.NoError(t, err)
+		require.Equal(t, sig, pi, name)
+		require.NoError(t, scheme.Verify(pk, []byte("alpha"), pi))
+
+		// but not the signatures with the default DSTs
+		sig, err = signers[name](bls.NewBLS12381Suite()).Sign(sk, []byte("alpha"))
+		require.NoError(t, err)
+		require.NotEqual(t, sig, pi, name)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	s := NewSchemeOnG1(bls.NewBLS12381Suite())
+	_, _, err := s.Prove(s.keys.Scalar().Zero(), []byte("alpha"))
+	require.Error(t, err)
+	_, err = s.ProofToHash(make([]byte, s.proofs.PointLen()))
+	require.Error(t, err)
+	_, err = s.ProofToHash(nil)
+	require.Error(t, err)
+}
+
+// TestKnownAnswer checks the vectors of testdata/vectors.json, computed with this package, whose proofs are the
+// kyber BLS signatures with the DSTs of the package, as checked by TestSignature.
+func TestKnownAnswer(t *testing.T) {
+	buf, err := os.ReadFile("testdata/vectors.json")
+	require.NoError(t, err)
+	var vectors []vector
+	require.NoError(t, json.Unmarshal(buf, &vectors))
+	require.NotEmpty(t, vectors)
+	for _, b := range backends {
+		suite := bls.NewBLS12381Suite(bls.WithBackend(b))
+		for i, v := range vectors {
+			s := schemes(suite)[v.Layout]
+			sk := s.keys.Scalar()
+			require.NoError(t, sk.UnmarshalBinary(decode(t, v.SK)))
+			pk := s.keys.Point()
+			require.NoError(t, pk.UnmarshalBinary(decode(t, v.PK)))
+			require.True(t, pk.Equal(s.PublicKey(sk)), "vector %d", i)
+			alpha := decode(t, v.Alpha)
+
+			pi, beta, err := s.Prove(sk, alpha)
+			require.NoError(t, err)
+			require.Equal(t, v.Pi, hex.EncodeToString(pi), "vector %d", i)
+			require.Equal(t, v.Beta, hex.EncodeToString(beta), "vector %d", i)
+			out, ok := s.Verify(pk, alpha, pi)
+			require.True(t, ok, "vector %d", i)
+			require.Equal(t, beta, out)
+		}
+	}
+}
+
+func decode(t *testing.T, s string) []byte {
+	buf, err := hex.DecodeString(s)
+	require.NoError(t, err)
+	return buf
+}